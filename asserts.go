@@ -9,11 +9,13 @@
 package it
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
 	"strings"
+	"time"
 )
 
 //
@@ -74,6 +76,90 @@ func Nil(x interface{}) error {
 	return passed(fmt.Errorf("nil value"))
 }
 
+//
+// Combinators
+//
+
+// Not negates the assert
+//
+//	it.Should(it.Not(it.Equal(x, y)))
+func Not(err error) error {
+	assert := &combinator{op: "not", children: []error{err}}
+
+	if isInvalid(err) {
+		return invalidInput(assert)
+	}
+
+	if IsPassed(err) {
+		return assert
+	}
+	return passed(assert)
+}
+
+// All asserts that every child assert is passed
+//
+//	it.Should(it.All(it.Greater(x, 1), it.Less(x, 10)))
+func All(errs ...error) error {
+	return combine("all of", errs, func(n int) bool { return n == len(errs) })
+}
+
+// Any asserts that at least one child assert is passed
+//
+//	it.Should(it.Any(it.Equal(x, 1), it.Equal(x, 10)))
+func Any(errs ...error) error {
+	return combine("any of", errs, func(n int) bool { return n > 0 })
+}
+
+// XOR asserts that exactly one child assert is passed
+//
+//	it.Should(it.XOR(it.Nil(resp), it.Nil(err)))
+func XOR(errs ...error) error {
+	return combine("exactly one of", errs, func(n int) bool { return n == 1 })
+}
+
+func combine(op string, errs []error, pred func(int) bool) error {
+	assert := &combinator{op: op, children: errs}
+
+	n := 0
+	for _, err := range errs {
+		if isInvalid(err) {
+			return invalidInput(assert)
+		}
+		if IsPassed(err) {
+			n++
+		}
+	}
+
+	if !pred(n) {
+		return assert
+	}
+	return passed(assert)
+}
+
+// combinator renders the tree of child asserts with their status
+type combinator struct {
+	op       string
+	children []error
+}
+
+func (c *combinator) Error() string {
+	var sb strings.Builder
+	sb.WriteString(c.op)
+
+	for _, err := range c.children {
+		mark, msg := "✓", "passed"
+		if !IsPassed(err) {
+			mark = "✗"
+		}
+		if err != nil {
+			msg = strings.ReplaceAll(err.Error(), "\n", "\n\t")
+		}
+		sb.WriteString(fmt.Sprintf("\n\t%s %s", mark, msg))
+	}
+
+	return sb.String()
+}
+
 //
 // Intercepts
 //
@@ -282,6 +368,316 @@ func GreaterOrEqual[T Orderable](x, y T) error {
 	return passed(assert)
 }
 
+// Comparable type constraint for types with Compare method
+// (e.g. time.Time, netip.Addr). Types with Cmp method (e.g. *big.Int,
+// *big.Float) or Less method are compared using BeforeFunc, AfterFunc, etc.
+type Comparable[T any] interface {
+	Compare(T) int
+}
+
+// Before compares (x < y) two variables of Comparable type
+//
+//	it.Should(it.Before(x, y))
+func Before[T Comparable[T]](x, y T) error {
+	return BeforeFunc(x, y, compareOf[T])
+}
+
+// BeforeOrEqual compares (x <= y) two variables of Comparable type
+//
+//	it.Should(it.BeforeOrEqual(x, y))
+func BeforeOrEqual[T Comparable[T]](x, y T) error {
+	return BeforeOrEqualFunc(x, y, compareOf[T])
+}
+
+// After compares (x > y) two variables of Comparable type
+//
+//	it.Should(it.After(x, y))
+func After[T Comparable[T]](x, y T) error {
+	return AfterFunc(x, y, compareOf[T])
+}
+
+// AfterOrEqual compares (x >= y) two variables of Comparable type
+//
+//	it.Should(it.AfterOrEqual(x, y))
+func AfterOrEqual[T Comparable[T]](x, y T) error {
+	return AfterOrEqualFunc(x, y, compareOf[T])
+}
+
+func compareOf[T Comparable[T]](x, y T) int { return x.Compare(y) }
+
+// BeforeFunc compares (x < y) two variables using comparator, which returns
+// negative number if x < y, zero if x == y and positive number if x > y.
+//
+//	it.Should(it.BeforeFunc(x, y, (*big.Int).Cmp))
+func BeforeFunc[T any](x, y T, cmp func(T, T) int) error {
+	assert := fmt.Errorf("%v be less than %v", x, y)
+	if !(cmp(x, y) < 0) {
+		return assert
+	}
+	return passed(assert)
+}
+
+// BeforeOrEqualFunc compares (x <= y) two variables using comparator
+//
+//	it.Should(it.BeforeOrEqualFunc(x, y, (*big.Int).Cmp))
+func BeforeOrEqualFunc[T any](x, y T, cmp func(T, T) int) error {
+	assert := fmt.Errorf("%v be less or equal to %v", x, y)
+	if !(cmp(x, y) <= 0) {
+		return assert
+	}
+	return passed(assert)
+}
+
+// AfterFunc compares (x > y) two variables using comparator
+//
+//	it.Should(it.AfterFunc(x, y, (*big.Int).Cmp))
+func AfterFunc[T any](x, y T, cmp func(T, T) int) error {
+	assert := fmt.Errorf("%v be greater than %v", x, y)
+	if !(cmp(x, y) > 0) {
+		return assert
+	}
+	return passed(assert)
+}
+
+// AfterOrEqualFunc compares (x >= y) two variables using comparator
+//
+//	it.Should(it.AfterOrEqualFunc(x, y, (*big.Int).Cmp))
+func AfterOrEqualFunc[T any](x, y T, cmp func(T, T) int) error {
+	assert := fmt.Errorf("%v be greater or equal to %v", x, y)
+	if !(cmp(x, y) >= 0) {
+		return assert
+	}
+	return passed(assert)
+}
+
+// CompareLess adapts the Less method (or function) to comparator
+//
+//	it.Should(it.BeforeFunc(x, y, it.CompareLess(Version.Less)))
+func CompareLess[T any](less func(T, T) bool) func(T, T) int {
+	return func(x, y T) int {
+		switch {
+		case less(x, y):
+			return -1
+		case less(y, x):
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+//
+// Reusable matchers
+//
+
+// MatcherOf is reusable Matcher, which is described in natural language.
+// Use Match method value as an assert function of other matchers:
+//
+//	adult := it.GreaterThan(18)
+//	it.Should(adult.Match(x))
+//	it.Should(it.Seq(xs).All(adult.Match))
+type MatcherOf[T any] struct {
+	desc   string
+	assert func(T) error
+}
+
+// Describe creates reusable matcher from predicate and its description
+//
+//	even := it.Describe("even", func(x int) bool { return x%2 == 0 })
+func Describe[T any](desc string, f func(T) bool) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   desc,
+		assert: func(x T) error { return Assertf(f(x), "%v be %s", x, desc) },
+	}
+}
+
+// Match applies matcher to the value
+func (m MatcherOf[T]) Match(x T) error { return m.assert(x) }
+
+// String describes the matcher in natural language
+func (m MatcherOf[T]) String() string { return m.desc }
+
+// EqualTo matches values equal (x = y) to y
+//
+//	it.Should(it.EqualTo(5).Match(x))
+func EqualTo[T comparable](y T) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   fmt.Sprintf("equal to %v", y),
+		assert: func(x T) error { return Equal(x, y) },
+	}
+}
+
+// EquivTo matches values equivalent (x ≈ y) to y
+//
+//	it.Should(it.EquivTo(obj).Match(x))
+func EquivTo[T any](y T) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   fmt.Sprintf("equivalent to %v", y),
+		assert: func(x T) error { return Equiv(x, y) },
+	}
+}
+
+// LessThan matches values less (x < y) than y
+func LessThan[T Orderable](y T) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   fmt.Sprintf("less than %v", y),
+		assert: func(x T) error { return Less(x, y) },
+	}
+}
+
+// LessOrEqualTo matches values less or equal (x <= y) to y
+func LessOrEqualTo[T Orderable](y T) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   fmt.Sprintf("less or equal to %v", y),
+		assert: func(x T) error { return LessOrEqual(x, y) },
+	}
+}
+
+// GreaterThan matches values greater (x > y) than y
+func GreaterThan[T Orderable](y T) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   fmt.Sprintf("greater than %v", y),
+		assert: func(x T) error { return Greater(x, y) },
+	}
+}
+
+// GreaterOrEqualTo matches values greater or equal (x >= y) to y
+func GreaterOrEqualTo[T Orderable](y T) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   fmt.Sprintf("greater or equal to %v", y),
+		assert: func(x T) error { return GreaterOrEqual(x, y) },
+	}
+}
+
+// InRangeOf matches values of closed interval [lo, hi]
+func InRangeOf[T Orderable](lo, hi T) MatcherOf[T] {
+	return MatcherOf[T]{
+		desc:   fmt.Sprintf("in range [%v, %v]", lo, hi),
+		assert: func(x T) error { return InRange(x, lo, hi) },
+	}
+}
+
+// OneOf matches values equal to one of ys
+func OneOf[T comparable](ys ...T) MatcherOf[T] {
+	return Describe(fmt.Sprintf("one of %v", ys), func(x T) bool {
+		for _, y := range ys {
+			if x == y {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+//
+// Ranges
+//
+
+// InRange checks that x belongs to closed interval (lo <= x <= hi)
+//
+//	it.Should(it.InRange(x, 1, 10))
+func InRange[T Orderable](x, lo, hi T) error {
+	return inRange(x, lo, hi, cmp.Compare(x, lo), cmp.Compare(x, hi), false, false)
+}
+
+// InOpenRange checks that x belongs to open interval (lo < x < hi)
+//
+//	it.Should(it.InOpenRange(x, 1, 10))
+func InOpenRange[T Orderable](x, lo, hi T) error {
+	return inRange(x, lo, hi, cmp.Compare(x, lo), cmp.Compare(x, hi), true, true)
+}
+
+// InLeftOpenRange checks that x belongs to left-open interval (lo < x <= hi)
+//
+//	it.Should(it.InLeftOpenRange(x, 1, 10))
+func InLeftOpenRange[T Orderable](x, lo, hi T) error {
+	return inRange(x, lo, hi, cmp.Compare(x, lo), cmp.Compare(x, hi), true, false)
+}
+
+// InRightOpenRange checks that x belongs to right-open interval (lo <= x < hi)
+//
+//	it.Should(it.InRightOpenRange(x, 1, 10))
+func InRightOpenRange[T Orderable](x, lo, hi T) error {
+	return inRange(x, lo, hi, cmp.Compare(x, lo), cmp.Compare(x, hi), false, true)
+}
+
+// Within checks that x of Comparable type belongs to closed interval
+// (lo <= x <= hi)
+//
+//	it.Should(it.Within(x, lo, hi))
+func Within[T Comparable[T]](x, lo, hi T) error {
+	return inRange(x, lo, hi, x.Compare(lo), x.Compare(hi), false, false)
+}
+
+// WithinOpen checks that x of Comparable type belongs to open interval
+// (lo < x < hi)
+//
+//	it.Should(it.WithinOpen(x, lo, hi))
+func WithinOpen[T Comparable[T]](x, lo, hi T) error {
+	return inRange(x, lo, hi, x.Compare(lo), x.Compare(hi), true, true)
+}
+
+// WithinLeftOpen checks that x of Comparable type belongs to left-open
+// interval (lo < x <= hi)
+//
+//	it.Should(it.WithinLeftOpen(x, lo, hi))
+func WithinLeftOpen[T Comparable[T]](x, lo, hi T) error {
+	return inRange(x, lo, hi, x.Compare(lo), x.Compare(hi), true, false)
+}
+
+// WithinRightOpen checks that x of Comparable type belongs to right-open
+// interval (lo <= x < hi)
+//
+//	it.Should(it.WithinRightOpen(x, lo, hi))
+func WithinRightOpen[T Comparable[T]](x, lo, hi T) error {
+	return inRange(x, lo, hi, x.Compare(lo), x.Compare(hi), false, true)
+}
+
+// Moment type constraint for scope of time asserts
+type Moment interface {
+	time.Time | time.Duration
+}
+
+// Between checks that time or duration belongs to closed interval [lo, hi]
+//
+//	it.Should(it.Between(t, time.Now().Add(-time.Hour), time.Now()))
+func Between[T Moment](x, lo, hi T) error {
+	return inRange(x, lo, hi, compareMoment(x, lo), compareMoment(x, hi), false, false)
+}
+
+func compareMoment[T Moment](x, y T) int {
+	switch xx := any(x).(type) {
+	case time.Time:
+		return xx.Compare(any(y).(time.Time))
+	case time.Duration:
+		return cmp.Compare(xx, any(y).(time.Duration))
+	default:
+		panic("runtime error")
+	}
+}
+
+// inRange asserts interval, given results of comparison x against its bounds
+func inRange(x, lo, hi any, xlo, xhi int, lopen, hopen bool) error {
+	lb, hb := "[", "]"
+	if lopen {
+		lb = "("
+	}
+	if hopen {
+		hb = ")"
+	}
+	assert := fmt.Sprintf("%v be in range %s%v, %v%s", x, lb, lo, hi, hb)
+
+	if xlo < 0 || (xlo == 0 && lopen) {
+		return fmt.Errorf("%s, violates lower bound %v", assert, lo)
+	}
+
+	if xhi > 0 || (xhi == 0 && hopen) {
+		return fmt.Errorf("%s, violates upper bound %v", assert, hi)
+	}
+
+	return passed(errors.New(assert))
+}
+
 //
 // Non public asserts
 //
@@ -9,7 +9,11 @@
 package it_test
 
 import (
+	"math/big"
+	"net/netip"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fogfish/it/v2"
 )
@@ -31,6 +35,50 @@ type err string
 func (e err) Error() string { return string(e) }
 func (e err) Behavior()     {}
 
+func TestNot(t *testing.T) {
+	it.Then(t).
+		Should(it.Not(it.Equal(1, 2))).
+		ShouldNot(it.Not(it.Equal(1, 1))).
+		Should(it.Not(it.Not(it.Equal(1, 1))))
+}
+
+func TestAll(t *testing.T) {
+	it.Then(t).
+		Should(it.All()).
+		Should(it.All(it.Greater(5, 1), it.Less(5, 10))).
+		ShouldNot(it.All(it.Greater(5, 1), it.Less(50, 10)))
+}
+
+func TestAny(t *testing.T) {
+	it.Then(t).
+		ShouldNot(it.Any()).
+		Should(it.Any(it.Equal(5, 1), it.Equal(5, 5))).
+		ShouldNot(it.Any(it.Equal(5, 1), it.Equal(5, 10)))
+}
+
+func TestXOR(t *testing.T) {
+	it.Then(t).
+		Should(it.XOR(it.Equal(5, 1), it.Equal(5, 5))).
+		ShouldNot(it.XOR(it.Equal(5, 5), it.Equal(5, 5))).
+		ShouldNot(it.XOR(it.Equal(5, 1), it.Equal(5, 10)))
+}
+
+func TestCombinatorMessage(t *testing.T) {
+	err := it.All(
+		it.Greater(5, 1),
+		it.Any(it.Equal(5, 1), it.Equal(5, 2)),
+	)
+
+	it.Then(t).
+		Should(it.Text(err.Error()).Equal(strings.Join([]string{
+			"all of",
+			"\t✓ 5 be greater than 1",
+			"\t✗ any of",
+			"\t\t✗ 5 be equal to 1",
+			"\t\t✗ 5 be equal to 2",
+		}, "\n")))
+}
+
 func TestFail(t *testing.T) {
 	fWithPanic := func() { panic(err("func with panic")) }
 	fNoPanic := func() {}
@@ -144,3 +192,174 @@ func TestGreaterOrEqual(t *testing.T) {
 		Should(it.GreaterOrEqual(1, 1)).
 		ShouldNot(it.GreaterOrEqual(0, 1))
 }
+
+func TestInRange(t *testing.T) {
+	it.Then(t).
+		Should(it.InRange(1, 1, 3)).
+		Should(it.InRange(3, 1, 3)).
+		ShouldNot(it.InRange(0, 1, 3)).
+		ShouldNot(it.InRange(4, 1, 3))
+}
+
+func TestInOpenRange(t *testing.T) {
+	it.Then(t).
+		Should(it.InOpenRange(2, 1, 3)).
+		ShouldNot(it.InOpenRange(1, 1, 3)).
+		ShouldNot(it.InOpenRange(3, 1, 3))
+}
+
+func TestInHalfOpenRange(t *testing.T) {
+	it.Then(t).
+		Should(it.InLeftOpenRange(3, 1, 3)).
+		ShouldNot(it.InLeftOpenRange(1, 1, 3)).
+		Should(it.InRightOpenRange(1, 1, 3)).
+		ShouldNot(it.InRightOpenRange(3, 1, 3))
+}
+
+func TestInRangeMessage(t *testing.T) {
+	it.Then(t).
+		Should(it.String(it.InRange(0, 1, 3).Error()).Contain("violates lower bound 1")).
+		Should(it.String(it.InRange(4, 1, 3).Error()).Contain("violates upper bound 3")).
+		Should(it.String(it.InOpenRange(3, 1, 3).Error()).Contain("range (1, 3)"))
+}
+
+func TestBetween(t *testing.T) {
+	now := time.Now()
+
+	it.Then(t).
+		Should(it.Between(now, now.Add(-time.Hour), now)).
+		ShouldNot(it.Between(now, now.Add(time.Second), now.Add(time.Hour))).
+		Should(it.Between(time.Second, time.Millisecond, time.Minute)).
+		ShouldNot(it.Between(time.Hour, time.Millisecond, time.Minute))
+}
+
+type version struct{ major, minor int }
+
+func (v version) Compare(x version) int {
+	if v.major != x.major {
+		return v.major - x.major
+	}
+	return v.minor - x.minor
+}
+
+func (v version) Less(x version) bool { return v.Compare(x) < 0 }
+
+func TestWithin(t *testing.T) {
+	now := time.Now()
+	lo, hi := version{1, 0}, version{2, 0}
+
+	it.Then(t).
+		Should(it.Within(now, now.Add(-time.Hour), now)).
+		ShouldNot(it.Within(now, now.Add(time.Second), now.Add(time.Hour))).
+		Should(it.Within(netip.MustParseAddr("10.0.0.5"), netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.9"))).
+		Should(it.Within(version{1, 5}, lo, hi)).
+		Should(it.Within(lo, lo, hi)).
+		ShouldNot(it.WithinOpen(lo, lo, hi)).
+		Should(it.WithinOpen(version{1, 5}, lo, hi)).
+		Should(it.WithinLeftOpen(hi, lo, hi)).
+		ShouldNot(it.WithinLeftOpen(lo, lo, hi)).
+		Should(it.WithinRightOpen(lo, lo, hi)).
+		ShouldNot(it.WithinRightOpen(hi, lo, hi)).
+		Should(it.String(it.Within(version{0, 9}, lo, hi).Error()).Contain("violates lower bound {1 0}")).
+		Should(it.String(it.WithinOpen(hi, lo, hi).Error()).Contain("violates upper bound {2 0}"))
+}
+
+func TestBefore(t *testing.T) {
+	now := time.Now()
+
+	it.Then(t).
+		Should(it.Before(now, now.Add(time.Second))).
+		ShouldNot(it.Before(now, now)).
+		Should(it.Before(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2"))).
+		Should(it.Before(version{1, 2}, version{1, 3})).
+		ShouldNot(it.Before(version{2, 0}, version{1, 3}))
+}
+
+func TestBeforeOrEqual(t *testing.T) {
+	now := time.Now()
+
+	it.Then(t).
+		Should(it.BeforeOrEqual(now, now.Add(time.Second))).
+		Should(it.BeforeOrEqual(now, now)).
+		ShouldNot(it.BeforeOrEqual(now.Add(time.Second), now))
+}
+
+func TestAfter(t *testing.T) {
+	now := time.Now()
+
+	it.Then(t).
+		Should(it.After(now.Add(time.Second), now)).
+		ShouldNot(it.After(now, now)).
+		Should(it.After(version{2, 0}, version{1, 3}))
+}
+
+func TestAfterOrEqual(t *testing.T) {
+	now := time.Now()
+
+	it.Then(t).
+		Should(it.AfterOrEqual(now.Add(time.Second), now)).
+		Should(it.AfterOrEqual(now, now)).
+		ShouldNot(it.AfterOrEqual(now, now.Add(time.Second)))
+}
+
+func TestBeforeFunc(t *testing.T) {
+	x, y := big.NewInt(10), big.NewInt(20)
+	less := it.CompareLess(version.Less)
+
+	it.Then(t).
+		Should(it.BeforeFunc(x, y, (*big.Int).Cmp)).
+		ShouldNot(it.BeforeFunc(x, x, (*big.Int).Cmp)).
+		Should(it.BeforeOrEqualFunc(x, x, (*big.Int).Cmp)).
+		ShouldNot(it.BeforeOrEqualFunc(y, x, (*big.Int).Cmp)).
+		Should(it.AfterFunc(y, x, (*big.Int).Cmp)).
+		ShouldNot(it.AfterFunc(x, x, (*big.Int).Cmp)).
+		Should(it.AfterOrEqualFunc(x, x, (*big.Int).Cmp)).
+		ShouldNot(it.AfterOrEqualFunc(x, y, (*big.Int).Cmp)).
+		Should(it.BeforeFunc(version{1, 2}, version{1, 3}, less)).
+		Should(it.BeforeOrEqualFunc(version{1, 3}, version{1, 3}, less)).
+		ShouldNot(it.AfterFunc(version{1, 3}, version{1, 3}, less)).
+		Should(it.Equal(it.BeforeFunc(y, x, (*big.Int).Cmp).Error(), "20 be less than 10")).
+		Should(it.Equal(it.AfterOrEqualFunc(x, y, (*big.Int).Cmp).Error(), "10 be greater or equal to 20"))
+}
+
+func TestMatcherOf(t *testing.T) {
+	adult := it.GreaterThan(18)
+	even := it.Describe("even", func(x int) bool { return x%2 == 0 })
+
+	it.Then(t).
+		Should(it.EqualTo(5).Match(5)).
+		ShouldNot(it.EqualTo(5).Match(6)).
+		Should(it.EquivTo([]int{1}).Match([]int{1})).
+		ShouldNot(it.EquivTo([]int{1}).Match([]int{2})).
+		Should(it.LessThan(5).Match(4)).
+		ShouldNot(it.LessThan(5).Match(5)).
+		Should(it.LessOrEqualTo(5).Match(5)).
+		ShouldNot(it.LessOrEqualTo(5).Match(6)).
+		Should(adult.Match(20)).
+		ShouldNot(adult.Match(18)).
+		Should(it.GreaterOrEqualTo(18).Match(18)).
+		ShouldNot(it.GreaterOrEqualTo(18).Match(17)).
+		Should(it.InRangeOf(1, 3).Match(3)).
+		ShouldNot(it.InRangeOf(1, 3).Match(4)).
+		Should(it.OneOf("a", "b").Match("b")).
+		ShouldNot(it.OneOf("a", "b").Match("c")).
+		Should(even.Match(4)).
+		ShouldNot(even.Match(3))
+}
+
+func TestMatcherOfReuse(t *testing.T) {
+	adult := it.GreaterThan(18)
+
+	it.Then(t).
+		Should(it.Equal(adult.String(), "greater than 18")).
+		Should(it.Equal(adult.Match(10).Error(), "10 be greater than 18")).
+		Should(it.String(it.InRangeOf(1, 3).Match(4).Error()).Contain("violates upper bound 3")).
+		Should(it.Seq([]int{20, 30}).All(adult.Match)).
+		ShouldNot(it.Seq([]int{20, 10}).All(adult.Match)).
+		Should(it.Map(map[string]int{"bob": 20}).HaveWith("bob", adult.Match)).
+		Should(it.Struct(struct{ Age int }{20}).Match(it.Fields{"Age": adult.Match}))
+
+	for _, x := range []int{19, 20, 100} {
+		it.Then(t).Should(adult.Match(x))
+	}
+}
@@ -0,0 +1,212 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+//
+// Golden files
+//
+
+// updateFlag is namespaced, so that it does not conflict with -update flag
+// defined by test suites
+const updateFlag = "it.update"
+
+func init() {
+	if flag.Lookup(updateFlag) == nil {
+		flag.Bool(updateFlag, false, "rewrite golden files at testdata")
+	}
+}
+
+func isUpdateGolden() bool {
+	if os.Getenv("IT_UPDATE") == "1" {
+		return true
+	}
+
+	f := flag.Lookup(updateFlag)
+	return f != nil && f.Value.String() == "true"
+}
+
+// Redactor replaces volatile content (timestamps, UUIDs) before comparison
+type Redactor func([]byte) ([]byte, error)
+
+// Redact replaces every match of regular expression with the string.
+// The invalid regular expression fails the Golden assert.
+//
+//	it.Golden(t, "name", actual, it.Redact(`"id":"[^"]*"`, `"id":"_"`))
+func Redact(pattern, with string) Redactor {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return func([]byte) ([]byte, error) {
+			return nil, fmt.Errorf("redactor %s be valid regex: %w", pattern, err)
+		}
+	}
+
+	return func(b []byte) ([]byte, error) { return re.ReplaceAll(b, []byte(with)), nil }
+}
+
+var (
+	// RedactUUID replaces UUIDs with <uuid>
+	RedactUUID = Redact(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`, "<uuid>")
+
+	// RedactTimestamp replaces RFC 3339 timestamps with <timestamp>
+	RedactTimestamp = Redact(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?`, "<timestamp>")
+)
+
+// Golden compares actual value against the golden file
+// testdata/<Test>/<name>.golden. Strings and text are compared line-by-line,
+// binary is compared byte-by-byte, other values are compared exactly as JSON
+// documents, the strings of golden file are data (e.g. "_" is not wildcard).
+// The golden files are rewritten if tests run with -it.update flag or IT_UPDATE=1.
+// The missing golden file fails the assert regardless of the keyword polarity.
+//
+//	it.Then(t).Should(it.Golden(t, "response", actual))
+func Golden(t *testing.T, name string, actual any, redactors ...Redactor) error {
+	t.Helper()
+	return golden(t, name, actual, false, redactors)
+}
+
+// GoldenPattern is Golden, which matches JSON values against the golden file
+// using the strict JsonOf.Equal semantic, the golden file contains patterns.
+// The update mode writes actual value, patterns have to be restored manually.
+//
+//	it.Then(t).Should(it.GoldenPattern(t, "response", actual))
+func GoldenPattern(t *testing.T, name string, actual any, redactors ...Redactor) error {
+	t.Helper()
+	return golden(t, name, actual, true, redactors)
+}
+
+func golden(t *testing.T, name string, actual any, pattern bool, redactors []Redactor) error {
+	t.Helper()
+
+	file := filepath.Join("testdata", filepath.FromSlash(t.Name()), name+".golden")
+	assert := fmt.Errorf("match golden file %s", file)
+
+	kind, value, err := goldenOf(actual)
+	if err != nil {
+		return fmt.Errorf("%w, value be serializable: %s", assert, err)
+	}
+
+	for _, f := range redactors {
+		if value, err = f(value); err != nil {
+			return invalidInput(fmt.Errorf("%w: %w", assert, err))
+		}
+	}
+
+	if isUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return fmt.Errorf("%w, file be writable: %s", assert, err)
+		}
+		if err := os.WriteFile(file, value, 0644); err != nil {
+			return fmt.Errorf("%w, file be writable: %s", assert, err)
+		}
+		return passed(fmt.Errorf("%s (updated)", assert))
+	}
+
+	expect, err := os.ReadFile(file)
+	if err != nil {
+		return invalidInput(fmt.Errorf("%w, file be readable (run with -it.update or IT_UPDATE=1 to create): %s", assert, err))
+	}
+
+	switch {
+	case kind == goldenText:
+		err = Text(string(value)).Equal(string(expect))
+	case kind == goldenJSON && pattern:
+		err = Json(json.RawMessage(value)).Equal(string(expect))
+	case kind == goldenJSON:
+		err = diffJSON(value, expect)
+	default:
+		err = diffBytes(value, expect)
+	}
+
+	if isInvalid(err) {
+		return invalidInput(fmt.Errorf("%w: %s", assert, err))
+	}
+
+	if !IsPassed(err) {
+		return fmt.Errorf("%w: %s", assert, err)
+	}
+
+	return passed(assert)
+}
+
+const (
+	goldenText = iota
+	goldenJSON
+	goldenBytes
+)
+
+func goldenOf(actual any) (int, []byte, error) {
+	switch v := actual.(type) {
+	case string:
+		return goldenText, []byte(v), nil
+	case []byte:
+		if utf8.Valid(v) {
+			return goldenText, v, nil
+		}
+		return goldenBytes, v, nil
+	default:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return goldenJSON, nil, err
+		}
+		return goldenJSON, append(b, '\n'), nil
+	}
+}
+
+// diffJSON compares JSON documents exactly, without pattern semantic
+func diffJSON(actual, expect []byte) error {
+	var val, doc any
+	if err := json.Unmarshal(actual, &val); err != nil {
+		return invalidInput(invalidJSON("input", actual, err))
+	}
+	if err := json.Unmarshal(expect, &doc); err != nil {
+		return invalidInput(invalidJSON("golden file", expect, err))
+	}
+
+	if dv := diffExact(doc, val); dv != nil {
+		var sb strings.Builder
+		p := newPrinter(&sb)
+		p.print("", "", dv)
+
+		return fmt.Errorf("be equal\n%s", strings.TrimSuffix(sb.String(), "\n"))
+	}
+
+	return passed(fmt.Errorf("be equal"))
+}
+
+func diffBytes(actual, expect []byte) error {
+	assert := fmt.Errorf("bytes be equal to expected")
+
+	if bytes.Equal(actual, expect) {
+		return passed(assert)
+	}
+
+	at := 0
+	for at < len(actual) && at < len(expect) && actual[at] == expect[at] {
+		at++
+	}
+
+	snippet := func(b []byte) []byte { return b[at:min(at+16, len(b))] }
+
+	return fmt.Errorf("%w, differ at offset %d (expected len %d, actual len %d)\n- % x\n+ % x",
+		assert, at, len(expect), len(actual), snippet(expect), snippet(actual),
+	)
+}
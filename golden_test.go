@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+)
+
+func TestGolden(t *testing.T) {
+	type User struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	type Admin struct {
+		User
+		Role string `json:"role"`
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		it.Then(t).
+			Should(it.Golden(t, "greeting", "Hello,\nWorld!\n")).
+			Should(it.Golden(t, "greeting", []byte("Hello,\nWorld!\n")))
+
+		if !updating() {
+			it.Then(t).
+				ShouldNot(it.Golden(t, "greeting", "Hello,\nGopher!\n"))
+		}
+	})
+
+	t.Run("Json", func(t *testing.T) {
+		it.Then(t).
+			Should(it.Golden(t, "user", User{ID: "8d1f", Name: "bob"}))
+
+		if !updating() {
+			it.Then(t).
+				ShouldNot(it.Golden(t, "user", User{ID: "8d1f", Name: "alice"})).
+				ShouldNot(it.Golden(t, "user", User{ID: "_", Name: "bob"})).
+				ShouldNot(it.Golden(t, "user", Admin{User{ID: "8d1f", Name: "bob"}, "root"}))
+		}
+	})
+
+	t.Run("JsonPattern", func(t *testing.T) {
+		if updating() {
+			t.Skip("golden file contains patterns")
+		}
+
+		it.Then(t).
+			Should(it.GoldenPattern(t, "user", User{ID: "8d1f", Name: "bob"})).
+			Should(it.GoldenPattern(t, "user", User{ID: "a2c4", Name: "bob"})).
+			ShouldNot(it.GoldenPattern(t, "user", User{ID: "8d1f", Name: "alice"})).
+			ShouldNot(it.GoldenPattern(t, "user", Admin{User{ID: "8d1f", Name: "bob"}, "root"}))
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		it.Then(t).
+			Should(it.Golden(t, "blob", []byte{0x00, 0x01, 0x02, 0xff}))
+
+		if !updating() {
+			it.Then(t).
+				ShouldNot(it.Golden(t, "blob", []byte{0x00, 0x01, 0x03, 0xff}))
+		}
+	})
+
+	t.Run("Redact", func(t *testing.T) {
+		it.Then(t).
+			Should(it.Golden(t, "event",
+				"id=0b7c2a5e-6a1d-4f4e-9b3c-2d7e8f9a0b1c at 2024-01-02T03:04:05.123Z\n",
+				it.RedactUUID, it.RedactTimestamp,
+			)).
+			Should(it.Golden(t, "event",
+				"id=xxx at 2024-01-02 03:04:05\n",
+				it.Redact(`xxx`, "<uuid>"), it.RedactTimestamp,
+			))
+	})
+
+	t.Run("JsonDiff", func(t *testing.T) {
+		err := it.Json(User{ID: "8d1f", Name: "alice"}).Equal(`{"id": "_", "name": "bob", "email": "_"}`)
+
+		it.Then(t).
+			ShouldNot(err).
+			Should(it.Golden(t, "diff", err.Error()))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		if updating() {
+			t.Skip("golden files are updated")
+		}
+
+		t.Setenv("IT_UPDATE", "1")
+		t.Cleanup(func() { os.RemoveAll(filepath.Join("testdata", "TestGolden", "Update")) })
+
+		it.Then(t).
+			Should(it.Golden(t, "created", "text")).
+			Should(it.Golden(t, "created", "changed"))
+
+		t.Setenv("IT_UPDATE", "")
+		it.Then(t).
+			Should(it.Golden(t, "created", "changed")).
+			ShouldNot(it.Golden(t, "created", "text"))
+	})
+}
+
+// updating detects update mode, the negative cases must not rewrite golden
+// files with wrong values
+func updating() bool {
+	f := flag.Lookup("it.update")
+	return os.Getenv("IT_UPDATE") == "1" || (f != nil && f.Value.String() == "true")
+}
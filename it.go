@@ -62,6 +62,11 @@ func (check *Check) MustNot(errs ...error) *Check {
 
 	for _, err := range errs {
 		if err != nil {
+			if isInvalid(err) {
+				check.fatalf("must %s", err)
+				continue
+			}
+
 			var e interface{ Passed() bool }
 			ok := errors.As(err, &e)
 			output := check.fatalf
@@ -108,6 +113,11 @@ func (check *Check) ShouldNot(errs ...error) *Check {
 
 	for _, err := range errs {
 		if err != nil {
+			if isInvalid(err) {
+				check.errorf("should %s", err)
+				continue
+			}
+
 			var e interface{ Passed() bool }
 			ok := errors.As(err, &e)
 			output := check.errorf
@@ -154,6 +164,11 @@ func (check *Check) MayNot(errs ...error) *Check {
 
 	for _, err := range errs {
 		if err != nil {
+			if isInvalid(err) {
+				check.warningf("may %s", err)
+				continue
+			}
+
 			var e interface{ Passed() bool }
 			ok := errors.As(err, &e)
 			output := check.warningf
@@ -203,6 +218,86 @@ func (check *Check) debugf(msg string, args ...any) {
 	check.t.Logf("%s", fmt.Sprintf(msg, args...))
 }
 
+//
+// Custom asserts
+//
+
+// Assert is the building block of custom matchers. The description states
+// the requirement in positive form (e.g. "amount %v be positive"), the
+// imperative keyword prefixes it with must, should or may, including its
+// negation, so that the message reads naturally in either case.
+//
+//	func Positive(x Money) error {
+//	  return it.Assert(fmt.Sprintf("amount %v be positive", x), x.Amount > 0)
+//	}
+//
+//	it.Then(t).Should(Positive(x))
+func Assert(description string, ok bool) error {
+	return Assertf(ok, "%s", description)
+}
+
+// Assertf is Assert with formatted description
+//
+//	it.Assertf(x.Amount > 0, "amount %v be positive", x)
+func Assertf(ok bool, format string, args ...any) error {
+	assert := fmt.Errorf(format, args...)
+
+	if !ok {
+		return assert
+	}
+	return passed(assert)
+}
+
+// IsPassed checks if assert is labeled with success. Nil error is passed.
+func IsPassed(err error) bool {
+	var e interface{ Passed() bool }
+	return err == nil || (errors.As(err, &e) && e.Passed())
+}
+
+// Matcher is the reusable assert of values of type T. The Match returns
+// the result of assert, which is either passed or failed.
+type Matcher[T any] interface {
+	Match(T) error
+}
+
+// MatcherFunc adapts the function to Matcher interface
+//
+//	var positive it.Matcher[Money] = it.MatcherFunc[Money](Positive)
+type MatcherFunc[T any] func(T) error
+
+func (f MatcherFunc[T]) Match(x T) error { return f(x) }
+
+// invalid labels assert that cannot be evaluated due to invalid input (e.g.
+// malformed pattern), it fails regardless of the keyword polarity
+type invalid struct{ err error }
+
+func invalidInput(err error) *invalid { return &invalid{err} }
+func (e *invalid) Error() string      { return e.err.Error() }
+func (e *invalid) Unwrap() error      { return e.err }
+func (e *invalid) Invalid() bool      { return true }
+
+func isInvalid(err error) bool {
+	var e interface{ Invalid() bool }
+	return errors.As(err, &e) && e.Invalid()
+}
+
+// nested labels assert with result of the child one, the message of child is
+// appended if any. The invalid input of child invalidates the assert.
+func nested(assert, err error) error {
+	if err != nil {
+		assert = fmt.Errorf("%w: %s", assert, err)
+	}
+
+	switch {
+	case isInvalid(err):
+		return invalidInput(assert)
+	case !IsPassed(err):
+		return assert
+	default:
+		return passed(assert)
+	}
+}
+
 // ok labels assert with success
 type ok struct{ err error }
 
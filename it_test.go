@@ -10,6 +10,9 @@ package it_test
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"os/exec"
 	"testing"
 
 	"github.com/fogfish/it/v2"
@@ -65,3 +68,101 @@ func TestImperativeKeywords(t *testing.T) {
 	it.Then(mock).May(failure())
 	it.Then(t).ShouldNot(it.Be(mock.Failed))
 }
+
+type money struct {
+	amount   int
+	currency string
+}
+
+func positive(x money) error {
+	return it.Assert(fmt.Sprintf("amount %d %s be positive", x.amount, x.currency), x.amount > 0)
+}
+
+func currency(c string) it.Matcher[money] {
+	return it.MatcherFunc[money](func(x money) error {
+		return it.Assertf(x.currency == c, "amount %d %s be in %s", x.amount, x.currency, c)
+	})
+}
+
+func TestCustomAssert(t *testing.T) {
+	it.Then(t).
+		Should(positive(money{10, "EUR"})).
+		ShouldNot(positive(money{-10, "EUR"})).
+		Should(currency("EUR").Match(money{10, "EUR"})).
+		ShouldNot(currency("USD").Match(money{10, "EUR"})).
+		Should(it.True(it.IsPassed(positive(money{10, "EUR"})))).
+		Should(it.True(it.IsPassed(nil))).
+		ShouldNot(it.True(it.IsPassed(errors.New("fail"))))
+
+	mock := new(testing.T)
+	it.Then(mock).Should(positive(money{-10, "EUR"}))
+	it.Then(t).Should(it.Be(mock.Failed))
+
+	mock = new(testing.T)
+	it.Then(mock).MayNot(positive(money{-10, "EUR"}))
+	it.Then(t).ShouldNot(it.Be(mock.Failed))
+}
+
+// TestInvalidInput runs asserts of invalid input within child process, the
+// assert fails regardless of the keyword polarity.
+func TestInvalidInput(t *testing.T) {
+	asserts := map[string]func() error{
+		"Regex":          func() error { return it.Json(`{"a": "x"}`).Equiv(`{"a": "regex:("}`) },
+		"TypeWildcard":   func() error { return it.Json(`{"a": 1}`).Equiv(`{"a": "_:nubmer"}`) },
+		"Number":         func() error { return it.Json(`{"a": 1}`).Equiv(`{"a": "num:>x"}`) },
+		"Input":          func() error { return it.Json(`garbage`).Equiv(`"_"`) },
+		"InputMarshal":   func() error { return it.Json(make(chan int)).Equiv(`"_"`) },
+		"InputSchema":    func() error { return it.Json(`garbage`).ConformTo(`{"type": "string"}`) },
+		"InputPath":      func() error { return it.Json(`garbage`).At("/a").Equal(1) },
+		"Pattern":        func() error { return it.Json(`{"a": 1}`).Equiv(`{"a": }`) },
+		"StringMatch":    func() error { return it.String("v2.1").Match(`^v(\d+`) },
+		"StringWith":     func() error { return it.String("v2.1").Match(`^v(\d+`).With("x", "1") },
+		"Schema":         func() error { return it.Json(`1`).ConformTo(`{"type": `) },
+		"SchemaType":     func() error { return it.Json(`{}`).ConformTo(`{"type": "objcet"}`) },
+		"SchemaKeyword":  func() error { return it.Json(`"x"`).ConformTo(`{"maxLength": "10"}`) },
+		"SchemaPattern":  func() error { return it.Json(`"x"`).ConformTo(`{"pattern": "(x"}`) },
+		"Not":            func() error { return it.Not(it.Json(`"x"`).Equiv(`"regex:("`)) },
+		"All":            func() error { return it.All(it.Equal(1, 1), it.Json(`"x"`).Equiv(`"regex:("`)) },
+		"GoldenNotFound": func() error { return it.Golden(t, "undefined", "text") },
+		"Redact":         func() error { return it.Golden(t, "undefined", "text", it.Redact(`(x`, "_")) },
+		"ValuePath":      func() error { return it.Value(struct{ Name string }{"bob"}).At("Name[").Equal("bob") },
+		"ValueField":     func() error { return it.Value(struct{ Name string }{"bob"}).At("Nmae").Equal("bob") },
+		"StructField":    func() error { return it.Struct(struct{ Name string }{"bob"}).Field("Email").Equal("bob") },
+		"StructMatch":    func() error { return it.Struct(struct{ Name string }{"bob"}).Match(it.Fields{"Nmae": "bob"}) },
+		"JsonPath":       func() error { return it.Json(`{"a": [1]}`).At("$.a[").Equal(1) },
+		"JsonPathRoot":   func() error { return it.Json(`{"a": [1]}`).At("a").Equal(1) },
+		"SeqAll": func() error {
+			return it.Seq([]string{"a"}).All(func(x string) error { return it.String(x).Match("(") })
+		},
+		"SeqNone": func() error {
+			return it.Seq([]string{"a"}).None(func(x string) error { return it.String(x).Match("(") })
+		},
+		"MapHaveWith": func() error {
+			return it.Map(map[string]string{"k": "v"}).HaveWith("k", func(x string) error { return it.String(x).Match("(") })
+		},
+		"SeqExactly": func() error {
+			return it.Seq([]string{"a"}).Exactly(0, func(x string) error { return it.String(x).Match("(") })
+		},
+	}
+
+	if os.Getenv("IT_INVALID_INPUT") == "1" {
+		for name, f := range asserts {
+			t.Run(name+"/ShouldNot", func(t *testing.T) { it.Then(t).ShouldNot(f()) })
+			t.Run(name+"/MustNot", func(t *testing.T) { it.Then(t).MustNot(f()) })
+		}
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInvalidInput$", "-test.v")
+	// golden files must not be created by the child process in update mode
+	cmd.Env = append(os.Environ(), "IT_INVALID_INPUT=1", "IT_UPDATE=")
+	out, err := cmd.CombinedOutput()
+
+	// the child process fails on purpose
+	it.Then(t).Should(it.True(err != nil))
+	for name := range asserts {
+		it.Then(t).
+			Should(it.String(out).Contain("--- FAIL: TestInvalidInput/" + name + "/ShouldNot")).
+			Should(it.String(out).Contain("--- FAIL: TestInvalidInput/" + name + "/MustNot"))
+	}
+}
@@ -0,0 +1,326 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//
+// JSON Pointer and JSONPath
+//
+
+// JsonAt is the value selected from JSON document
+type JsonAt struct {
+	path   string
+	values []any
+	multi  bool
+	near   any
+	err    error
+}
+
+// At selects value from JSON document using either JSON Pointer (RFC 6901),
+// e.g. /items/0/name, or subset of JSONPath, e.g. $.items[*].price. The
+// JSONPath supports child .name or ['name'], index [0] (negative one counts
+// from the end) and wildcards [*], .*, the wildcards select sequence of values,
+// members without selected child are skipped.
+// The malformed path fails the assert regardless of the keyword polarity.
+//
+//	it.Should(it.Json(obj).At("/items/0/name").Equal("x"))
+func (obj JsonOf[A]) At(path string) JsonAt {
+	doc, err := obj.value()
+	if err != nil {
+		return JsonAt{path: path, err: err}
+	}
+
+	var steps []jsonStep
+	switch {
+	case path == "" || path[0] == '/':
+		steps, err = parseJsonPointer(path)
+	case path[0] == '$':
+		steps, err = parseJsonPath(path)
+	default:
+		err = fmt.Errorf("path be JSON Pointer or JSONPath")
+	}
+	if err != nil {
+		return JsonAt{path: path, err: invalidInput(err)}
+	}
+
+	at := JsonAt{path: path, values: []any{doc}, near: doc}
+	for _, step := range steps {
+		seq := make([]any, 0)
+		for _, v := range at.values {
+			xs, err := step.selectOf(v)
+			if err != nil && at.multi {
+				// wildcard selection skips members that do not exist
+				continue
+			}
+			if err != nil {
+				return JsonAt{path: path, near: v, err: err}
+			}
+			seq = append(seq, xs...)
+		}
+		if len(at.values) == 1 {
+			at.near = at.values[0]
+		}
+		at.values = seq
+		at.multi = at.multi || step.wildcard
+	}
+
+	return at
+}
+
+// Value returns the selected value, the sequence of values is returned as
+// []any if path contains wildcards.
+func (at JsonAt) Value() (any, error) {
+	if at.err != nil {
+		// path syntax and input errors have no document context
+		if at.near == nil {
+			return nil, fmt.Errorf("path %s be resolvable: %w", at.path, at.err)
+		}
+		return nil, fmt.Errorf("path %s be resolvable: %w, near %s", at.path, at.err, snippetOf(at.near))
+	}
+
+	if at.multi {
+		return at.values, nil
+	}
+
+	return at.values[0], nil
+}
+
+// Equal checks equality of the selected value, the expected value is
+// compared using JSON semantic (e.g. numbers are equal regardless of the type)
+//
+//	it.Should(it.Json(obj).At("/items/0/price").Equal(10))
+func (at JsonAt) Equal(y any) error {
+	x, err := at.Value()
+	if err != nil {
+		return err
+	}
+
+	var expect any
+	raw, err := json.Marshal(y)
+	if err != nil {
+		return fmt.Errorf("expected value %v be valid JSON: %w", y, err)
+	}
+	if err := json.Unmarshal(raw, &expect); err != nil {
+		return fmt.Errorf("expected value %v be valid JSON: %w", y, err)
+	}
+
+	assert := fmt.Errorf("value %s at %s be equal to %s", snippetOf(x), at.path, raw)
+
+	if !equal(x, expect) {
+		return fmt.Errorf("%w, near %s", assert, snippetOf(at.near))
+	}
+
+	return passed(assert)
+}
+
+// Equiv matches selected value against patterns
+//
+//	it.Should(it.Json(obj).At("/items/0").Equiv(`{"name": "_"}`))
+func (at JsonAt) Equiv(shapes ...string) error {
+	x, err := at.Value()
+	if err != nil {
+		return err
+	}
+
+	return Json(json.RawMessage(jsonOf(x))).Equiv(shapes...)
+}
+
+// JsonAs decodes the selected value into type T and applies the assert, it
+// hands the value to String, Seq, Map, numeric and other typed matchers
+//
+//	it.Should(it.JsonAs(it.Json(obj).At("$.items[*].price"), func(xs []float64) error {
+//	  return it.Seq(xs).All(it.GreaterThan(0.0).Match)
+//	}))
+func JsonAs[T any](at JsonAt, f func(T) error) error {
+	x, err := at.Value()
+	if err != nil {
+		return err
+	}
+
+	var t T
+	if err := json.Unmarshal(jsonOf(x), &t); err != nil {
+		return fmt.Errorf("value %s at %s be decodable to %T: %w", snippetOf(x), at.path, t, err)
+	}
+
+	return nested(fmt.Errorf("value at %s", at.path), f(t))
+}
+
+func jsonOf(x any) []byte {
+	raw, _ := json.Marshal(x)
+	return raw
+}
+
+// snippetOf renders JSON value truncated to reasonable length
+func snippetOf(x any) string {
+	const size = 60
+
+	s := string(jsonOf(x))
+	if len(s) > size {
+		return s[:size] + "..."
+	}
+	return s
+}
+
+//------------------------------------------------------------------------------
+
+// jsonStep selects children of JSON value, either key of object or index of
+// array, the wildcard selects all children.
+type jsonStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// pointerAt selects array element by JSON Pointer token, which is either
+// non-negative integer without leading zeros or "-" (past the last element),
+// as defined by RFC 6901.
+func pointerAt(seq []any, token string) ([]any, error) {
+	if token == "-" {
+		return nil, fmt.Errorf("index - out of range, it refers past the last element, length %d", len(seq))
+	}
+
+	if !reIndexToken.MatchString(token) {
+		return nil, fmt.Errorf("index %s of array be non-negative integer without leading zeros", token)
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= len(seq) {
+		return nil, fmt.Errorf("index %s out of range, length %d", token, len(seq))
+	}
+	return []any{seq[i]}, nil
+}
+
+var reIndexToken = regexp.MustCompile(`^(?:0|[1-9][0-9]*)$`)
+
+func (s jsonStep) selectOf(v any) ([]any, error) {
+	switch vv := v.(type) {
+	case map[string]any:
+		if s.wildcard {
+			keys := make([]string, 0, len(vv))
+			for k := range vv {
+				keys = append(keys, k)
+			}
+			sortKeys(keys)
+
+			seq := make([]any, 0, len(vv))
+			for _, k := range keys {
+				seq = append(seq, vv[k])
+			}
+			return seq, nil
+		}
+
+		key := s.key
+		if s.isIndex {
+			key = strconv.Itoa(s.index)
+		}
+		x, has := vv[key]
+		if !has {
+			return nil, fmt.Errorf("key %s not found", key)
+		}
+		return []any{x}, nil
+	case []any:
+		if s.wildcard {
+			return vv, nil
+		}
+
+		if !s.isIndex {
+			return pointerAt(vv, s.key)
+		}
+
+		// negative index of JSONPath counts from the end of array
+		at, i := s.index, s.index
+		if i < 0 {
+			i = len(vv) + i
+		}
+		if i < 0 || i >= len(vv) {
+			return nil, fmt.Errorf("index %d out of range, length %d", at, len(vv))
+		}
+		return []any{vv[i]}, nil
+	default:
+		if s.wildcard {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s is not navigable", snippetOf(v))
+	}
+}
+
+func parseJsonPointer(path string) ([]jsonStep, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	seq := make([]jsonStep, 0)
+	for _, token := range strings.Split(path[1:], "/") {
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+		seq = append(seq, jsonStep{key: token})
+	}
+
+	return seq, nil
+}
+
+// escapeJsonPointer escapes the reference token of JSON Pointer
+func escapeJsonPointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func parseJsonPath(path string) ([]jsonStep, error) {
+	seq := make([]jsonStep, 0)
+
+	for i := 1; i < len(path); {
+		switch path[i] {
+		case '.':
+			j := strings.IndexAny(path[i+1:], ".[")
+			if j == -1 {
+				j = len(path) - i - 1
+			}
+			name := path[i+1 : i+1+j]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("empty name at %d", i)
+			case "*":
+				seq = append(seq, jsonStep{wildcard: true})
+			default:
+				seq = append(seq, jsonStep{key: name})
+			}
+			i = i + 1 + j
+		case '[':
+			j := strings.IndexByte(path[i:], ']')
+			if j == -1 {
+				return nil, fmt.Errorf("unclosed bracket at %d", i)
+			}
+			term := path[i+1 : i+j]
+			switch {
+			case term == "*":
+				seq = append(seq, jsonStep{wildcard: true})
+			case len(term) >= 2 && (term[0] == '\'' || term[0] == '"') && term[len(term)-1] == term[0]:
+				seq = append(seq, jsonStep{key: term[1 : len(term)-1]})
+			default:
+				n, err := strconv.Atoi(term)
+				if err != nil {
+					return nil, fmt.Errorf("index [%s] be integer", term)
+				}
+				seq = append(seq, jsonStep{index: n, isIndex: true})
+			}
+			i = i + j + 1
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", path[i], i)
+		}
+	}
+
+	return seq, nil
+}
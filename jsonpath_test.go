@@ -0,0 +1,127 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/it/v2"
+)
+
+const orderDoc = `{
+	"id": "o1",
+	"items": [
+		{"name": "apple", "price": 1.5, "tags": ["fruit"]},
+		{"name": "pear", "price": 2, "tags": []}
+	],
+	"a/b": {"~c": true}
+}`
+
+func TestJsonPointer(t *testing.T) {
+	it.Then(t).
+		Should(it.Json(orderDoc).At("/id").Equal("o1")).
+		Should(it.Json(orderDoc).At("/items/0/name").Equal("apple")).
+		Should(it.Json(orderDoc).At("/items/1/price").Equal(2)).
+		Should(it.Json(orderDoc).At("/items/0/tags").Equal([]string{"fruit"})).
+		Should(it.Json(orderDoc).At("/a~1b/~0c").Equal(true)).
+		Should(it.Json(orderDoc).At("/items/1").Equiv(`{"name": "pear"}`)).
+		Should(it.Json(orderDoc).At("").Equiv(`{"id": "o1"}`)).
+		ShouldNot(it.Json(orderDoc).At("/items/0/name").Equal("pear")).
+		ShouldNot(it.Json(orderDoc).At("/items/5/name").Equal("pear")).
+		ShouldNot(it.Json(orderDoc).At("/items/x").Equal("pear")).
+		ShouldNot(it.Json(orderDoc).At("/items/0/name/x").Equal("pear")).
+		ShouldNot(it.Json(orderDoc).At("/owner").Equal("pear"))
+}
+
+func TestJsonPointerIndex(t *testing.T) {
+	for token, reason := range map[string]string{
+		"-1": "index -1 of array be non-negative integer without leading zeros",
+		"01": "index 01 of array be non-negative integer without leading zeros",
+		"+1": "index +1 of array be non-negative integer without leading zeros",
+		"-":  "index - out of range, it refers past the last element, length 2",
+	} {
+		err := it.Json(orderDoc).At("/items/" + token + "/name").Equal("pear")
+		it.Then(t).
+			ShouldNot(err).
+			Should(it.String(err.Error()).Contain(reason))
+	}
+
+	it.Then(t).
+		Should(it.Json(`{"01": "x", "-1": "y"}`).At("/01").Equal("x")).
+		Should(it.Json(`{"01": "x", "-1": "y"}`).At("/-1").Equal("y")).
+		Should(it.Json(orderDoc).At("$.items[-2].name").Equal("apple"))
+}
+
+func TestJsonPath(t *testing.T) {
+	it.Then(t).
+		Should(it.Json(orderDoc).At("$.id").Equal("o1")).
+		Should(it.Json(orderDoc).At("$.items[0].name").Equal("apple")).
+		Should(it.Json(orderDoc).At("$['items'][-1]['name']").Equal("pear")).
+		Should(it.Json(orderDoc).At("$.items[*].price").Equal([]float64{1.5, 2})).
+		Should(it.Json(orderDoc).At("$.items.*.name").Equal([]string{"apple", "pear"})).
+		Should(it.Json(orderDoc).At("$.items[*].tags[*]").Equal([]string{"fruit"})).
+		Should(it.Json(orderDoc).At("$").Equiv(`{"id": "o1"}`)).
+		ShouldNot(it.Json(orderDoc).At("$.items[2].name").Equal("apple"))
+
+	mixed := `{"a": [{"p": 1}, {"q": 2}, 3, {"p": {"r": 4}}, []]}`
+
+	it.Then(t).
+		Should(it.Json(mixed).At("$.a[*].p").Equal([]any{1, map[string]int{"r": 4}})).
+		Should(it.Json(mixed).At("$.a[*].p.r").Equal([]int{4})).
+		Should(it.Json(mixed).At("$.a[*][0]").Equal([]int{})).
+		Should(it.Json(mixed).At("$.a[*].x").Equal([]int{}))
+}
+
+func TestJsonAs(t *testing.T) {
+	it.Then(t).
+		Should(it.JsonAs(it.Json(orderDoc).At("$.items[*].price"), func(xs []float64) error {
+			return it.Seq(xs).All(it.GreaterThan(0.0).Match)
+		})).
+		Should(it.JsonAs(it.Json(orderDoc).At("/items/0/name"), func(x string) error {
+			return it.String(x).HavePrefix("app")
+		})).
+		Should(it.JsonAs(it.Json(orderDoc).At("/items/1/price"), func(x int) error {
+			return it.InRange(x, 1, 3)
+		})).
+		ShouldNot(it.JsonAs(it.Json(orderDoc).At("/items/0/name"), func(x int) error {
+			return nil
+		})).
+		ShouldNot(it.JsonAs(it.Json(orderDoc).At("/items/0/name"), func(x string) error {
+			return it.String(x).HavePrefix("pe")
+		}))
+}
+
+func TestJsonAtMessage(t *testing.T) {
+	unresolved := it.Json(orderDoc).At("/items/5/name").Equal("x").Error()
+	mismatch := it.Json(orderDoc).At("/items/0/name").Equal("pear").Error()
+
+	it.Then(t).
+		Should(it.String(unresolved).Contain("path /items/5/name be resolvable: index 5 out of range, length 2")).
+		Should(it.String(unresolved).Contain(`near [{"name":"apple"`)).
+		Should(it.String(mismatch).Contain(`value "apple" at /items/0/name be equal to "pear"`)).
+		Should(it.String(mismatch).Contain(`near {"name":"apple","price":1.5,"tags":["fruit"]}`))
+
+	// malformed paths are invalid input, see TestInvalidInput
+	for path, reason := range map[string]string{
+		"$.items[x]": "path $.items[x] be resolvable: index [x] be integer",
+		"$.items[0":  "path $.items[0 be resolvable: unclosed bracket at 7",
+		"items":      "path items be resolvable: path be JSON Pointer or JSONPath",
+	} {
+		err := it.Json(orderDoc).At(path).Equal("apple").Error()
+
+		it.Then(t).
+			Should(it.Equal(err, reason))
+	}
+
+	passed := it.JsonAs(it.Json(orderDoc).At("/id"), func(x string) error { return nil })
+
+	it.Then(t).
+		Should(passed).
+		Should(it.Equal(passed.Error(), "value at /id"))
+}
@@ -0,0 +1,420 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+//
+// JSON Schema
+//
+
+// ConformTo validates input against JSON Schema. It supports the subset of
+// draft 2020-12: type, enum, const, properties, required,
+// additionalProperties, items, pattern, minLength, maxLength, minimum,
+// maximum, exclusiveMinimum, exclusiveMaximum, minItems, maxItems, allOf,
+// anyOf, oneOf and $ref within the schema document. The failure reports
+// every violation with its JSON pointer.
+//
+//	it.Should(it.Json(obj).ConformTo(`{"type": "object", "required": ["id"]}`))
+func (obj JsonOf[A]) ConformTo(schema string) error {
+	val, err := obj.value()
+	if err != nil {
+		return err
+	}
+
+	var root any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return invalidInput(invalidJSON("schema", []byte(schema), err))
+	}
+
+	v := &schemaOf{root: root}
+	v.validate("", root, val, 0)
+
+	if len(v.invalid) != 0 {
+		return invalidInput(fmt.Errorf("schema be valid\n\t%s", strings.Join(v.invalid, "\n\t")))
+	}
+
+	assert := "conform to schema"
+	if len(v.violations) != 0 {
+		return fmt.Errorf("%s\n\t%s", assert, strings.Join(v.violations, "\n\t"))
+	}
+
+	return passed(fmt.Errorf("%s", assert))
+}
+
+// schemaOf validates JSON value against the schema, collecting violations
+// of the value and errors of the schema itself
+type schemaOf struct {
+	root       any
+	violations []string
+	invalid    []string
+}
+
+// maximum depth of $ref resolution, it protects against cyclic references
+const schemaMaxDepth = 64
+
+func (v *schemaOf) violate(ptr string, format string, args ...any) {
+	if ptr == "" {
+		ptr = "(root)"
+	}
+	v.violations = append(v.violations, ptr+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *schemaOf) invalidate(ptr string, format string, args ...any) {
+	if ptr == "" {
+		ptr = "(root)"
+	}
+	if msg := ptr + ": " + fmt.Sprintf(format, args...); !slices.Contains(v.invalid, msg) {
+		v.invalid = append(v.invalid, msg)
+	}
+}
+
+func (v *schemaOf) validate(ptr string, schema any, val any, depth int) {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.violate(ptr, "value %s is not allowed", snippetOf(val))
+		}
+		return
+	case map[string]any:
+		v.validateObjectOf(ptr, s, val, depth)
+	default:
+		v.invalidate(ptr, "schema %s be object or boolean", snippetOf(schema))
+	}
+}
+
+func (v *schemaOf) validateObjectOf(ptr string, s map[string]any, val any, depth int) {
+	if !v.validKeywords(ptr, s) {
+		return
+	}
+
+	if ref, has := s["$ref"].(string); has {
+		if depth > schemaMaxDepth {
+			v.invalidate(ptr, "$ref %s be resolvable, too deep recursion", ref)
+			return
+		}
+		sub, err := v.resolve(ref)
+		if err != nil {
+			v.invalidate(ptr, "$ref %s be resolvable: %s", ref, err)
+			return
+		}
+		v.validate(ptr, sub, val, depth+1)
+	}
+
+	if t, has := s["type"]; has && !isSchemaTypeOf(t, val) {
+		v.violate(ptr, "value %s be of type %s", snippetOf(val), snippetOf(t))
+		return
+	}
+
+	if enum, has := s["enum"].([]any); has {
+		found := false
+		for _, e := range enum {
+			if equal(e, val) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.violate(ptr, "value %s be one of %s", snippetOf(val), snippetOf(enum))
+		}
+	}
+
+	if c, has := s["const"]; has && !equal(c, val) {
+		v.violate(ptr, "value %s be equal to %s", snippetOf(val), snippetOf(c))
+	}
+
+	v.validateCombinators(ptr, s, val, depth)
+
+	switch vv := val.(type) {
+	case string:
+		v.validateString(ptr, s, vv)
+	case float64:
+		v.validateNumber(ptr, s, vv)
+	case []any:
+		v.validateArray(ptr, s, vv, depth)
+	case map[string]any:
+		v.validateObject(ptr, s, vv, depth)
+	}
+}
+
+func (v *schemaOf) validateCombinators(ptr string, s map[string]any, val any, depth int) {
+	if seq, has := s["allOf"].([]any); has {
+		for _, sub := range seq {
+			v.validate(ptr, sub, val, depth)
+		}
+	}
+
+	count := func(seq []any) int {
+		n := 0
+		for _, sub := range seq {
+			if v.conform(sub, val, depth) {
+				n++
+			}
+		}
+		return n
+	}
+
+	if seq, has := s["anyOf"].([]any); has && count(seq) == 0 {
+		v.violate(ptr, "value %s be valid against any of schemas", snippetOf(val))
+	}
+
+	if seq, has := s["oneOf"].([]any); has {
+		if n := count(seq); n != 1 {
+			v.violate(ptr, "value %s be valid against exactly one of schemas, valid against %d", snippetOf(val), n)
+		}
+	}
+}
+
+// validKeywords checks values of keywords, so that typo of the schema (e.g.
+// unknown type name) is not reported as violation of the document
+func (v *schemaOf) validKeywords(ptr string, s map[string]any) bool {
+	valid := true
+	invalidate := func(format string, args ...any) {
+		v.invalidate(ptr, format, args...)
+		valid = false
+	}
+
+	if t, has := s["type"]; has {
+		types, ok := t.([]any)
+		if !ok {
+			types = []any{t}
+		}
+		for _, x := range types {
+			if name, ok := x.(string); !ok || !isSchemaTypeName(name) {
+				invalidate("type %s be one of null, boolean, object, array, number, integer or string", snippetOf(x))
+			}
+		}
+	}
+
+	if required, has := s["required"]; has {
+		seq, ok := required.([]any)
+		if !ok {
+			invalidate("required %s be array of strings", snippetOf(required))
+		}
+		for _, r := range seq {
+			if _, ok := r.(string); !ok {
+				invalidate("required %s be array of strings", snippetOf(required))
+				break
+			}
+		}
+	}
+
+	for _, k := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum"} {
+		if x, has := s[k]; has {
+			if _, ok := x.(float64); !ok {
+				invalidate("%s %s be number", k, snippetOf(x))
+			}
+		}
+	}
+
+	for _, k := range []string{"minLength", "maxLength", "minItems", "maxItems"} {
+		if x, has := s[k]; has {
+			if n, ok := x.(float64); !ok || n < 0 || n != math.Trunc(n) {
+				invalidate("%s %s be non-negative integer", k, snippetOf(x))
+			}
+		}
+	}
+
+	if x, has := s["pattern"]; has {
+		if _, ok := x.(string); !ok {
+			invalidate("pattern %s be string", snippetOf(x))
+		}
+	}
+
+	for _, k := range []string{"enum", "allOf", "anyOf", "oneOf"} {
+		if x, has := s[k]; has {
+			if _, ok := x.([]any); !ok {
+				invalidate("%s %s be array", k, snippetOf(x))
+			}
+		}
+	}
+
+	if x, has := s["properties"]; has {
+		if _, ok := x.(map[string]any); !ok {
+			invalidate("properties %s be object", snippetOf(x))
+		}
+	}
+
+	return valid
+}
+
+func isSchemaTypeName(t string) bool {
+	switch t {
+	case "null", "boolean", "object", "array", "number", "integer", "string":
+		return true
+	default:
+		return false
+	}
+}
+
+// conform checks the value against sub-schema, violations are discarded
+func (v *schemaOf) conform(schema any, val any, depth int) bool {
+	sub := &schemaOf{root: v.root}
+	sub.validate("", schema, val, depth)
+	for _, msg := range sub.invalid {
+		if !slices.Contains(v.invalid, msg) {
+			v.invalid = append(v.invalid, msg)
+		}
+	}
+	return len(sub.violations) == 0
+}
+
+func (v *schemaOf) validateString(ptr string, s map[string]any, x string) {
+	n := utf8.RuneCountInString(x)
+
+	if min, has := s["minLength"].(float64); has && float64(n) < min {
+		v.violate(ptr, "string %q length be greater or equal to %v", x, min)
+	}
+
+	if max, has := s["maxLength"].(float64); has && float64(n) > max {
+		v.violate(ptr, "string %q length be less or equal to %v", x, max)
+	}
+
+	if pattern, has := s["pattern"].(string); has {
+		re, err := compileRegex(pattern)
+		switch {
+		case err != nil:
+			v.invalidate(ptr, "schema pattern %s be valid regex: %s", pattern, err)
+		case !re.MatchString(x):
+			v.violate(ptr, "string %q match %s", x, pattern)
+		}
+	}
+}
+
+func (v *schemaOf) validateNumber(ptr string, s map[string]any, x float64) {
+	if min, has := s["minimum"].(float64); has && x < min {
+		v.violate(ptr, "%v be greater or equal to %v", x, min)
+	}
+
+	if max, has := s["maximum"].(float64); has && x > max {
+		v.violate(ptr, "%v be less or equal to %v", x, max)
+	}
+
+	if min, has := s["exclusiveMinimum"].(float64); has && x <= min {
+		v.violate(ptr, "%v be greater than %v", x, min)
+	}
+
+	if max, has := s["exclusiveMaximum"].(float64); has && x >= max {
+		v.violate(ptr, "%v be less than %v", x, max)
+	}
+}
+
+func (v *schemaOf) validateArray(ptr string, s map[string]any, xs []any, depth int) {
+	if min, has := s["minItems"].(float64); has && float64(len(xs)) < min {
+		v.violate(ptr, "array length %d be greater or equal to %v", len(xs), min)
+	}
+
+	if max, has := s["maxItems"].(float64); has && float64(len(xs)) > max {
+		v.violate(ptr, "array length %d be less or equal to %v", len(xs), max)
+	}
+
+	if items, has := s["items"]; has {
+		for i, x := range xs {
+			v.validate(fmt.Sprintf("%s/%d", ptr, i), items, x, depth)
+		}
+	}
+}
+
+func (v *schemaOf) validateObject(ptr string, s map[string]any, obj map[string]any, depth int) {
+	if required, has := s["required"].([]any); has {
+		for _, r := range required {
+			if key, ok := r.(string); ok {
+				if _, exists := obj[key]; !exists {
+					v.violate(ptr, "object have required key %s", key)
+				}
+			}
+		}
+	}
+
+	props, _ := s["properties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+
+	for _, k := range keys {
+		at := ptr + "/" + escapeJsonPointer(k)
+
+		if sub, has := props[k]; has {
+			v.validate(at, sub, obj[k], depth)
+			continue
+		}
+
+		if hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.violate(at, "key %s is not allowed by additionalProperties", k)
+				continue
+			}
+			v.validate(at, additional, obj[k], depth)
+		}
+	}
+}
+
+// resolve $ref within the schema document, e.g. #/$defs/address
+func (v *schemaOf) resolve(ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only references within the document are supported")
+	}
+
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("anchors are not supported, use JSON Pointer (e.g. #/$defs/name)")
+	}
+
+	steps, err := parseJsonPointer(ref[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	node := v.root
+	for _, step := range steps {
+		seq, err := step.selectOf(node)
+		if err != nil {
+			return nil, err
+		}
+		node = seq[0]
+	}
+
+	return node, nil
+}
+
+func isSchemaTypeOf(t any, val any) bool {
+	switch tt := t.(type) {
+	case string:
+		return isSchemaType(tt, val)
+	case []any:
+		for _, x := range tt {
+			if s, ok := x.(string); ok && isSchemaType(s, val) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isSchemaType(t string, val any) bool {
+	switch t {
+	case "integer":
+		x, ok := val.(float64)
+		return ok && x == math.Trunc(x)
+	case "boolean":
+		return isTypeOf("bool", val)
+	default:
+		return isTypeOf(t, val)
+	}
+}
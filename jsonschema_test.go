@@ -0,0 +1,106 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+)
+
+const schema = `{
+	"$defs": {
+		"item": {
+			"type": "object",
+			"required": ["sku", "qty"],
+			"properties": {
+				"sku": {"type": "string", "pattern": "^[A-Z][0-9]+$"},
+				"qty": {"type": "integer", "minimum": 1, "maximum": 10}
+			},
+			"additionalProperties": false
+		}
+	},
+	"type": "object",
+	"required": ["id", "items"],
+	"properties": {
+		"id": {"type": "string", "minLength": 2, "maxLength": 8},
+		"status": {"enum": ["new", "paid"]},
+		"score": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+		"note": {"type": ["string", "null"]},
+		"items": {"type": "array", "minItems": 1, "maxItems": 3, "items": {"$ref": "#/$defs/item"}}
+	}
+}`
+
+func TestJsonSchema(t *testing.T) {
+	type Item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type Order struct {
+		ID    string `json:"id"`
+		Items []Item `json:"items"`
+	}
+
+	it.Then(t).
+		Should(it.Json(`{"id": "o1", "status": "new", "score": 0.5, "note": null, "items": [{"sku": "A1", "qty": 2}]}`).ConformTo(schema)).
+		Should(it.Json(Order{ID: "o1", Items: []Item{{"A1", 1}, {"B2", 10}}}).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1"}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o", "items": [{"sku": "A1", "qty": 2}]}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1", "status": "old", "items": [{"sku": "A1", "qty": 2}]}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1", "score": 1, "items": [{"sku": "A1", "qty": 2}]}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1", "note": 1, "items": [{"sku": "A1", "qty": 2}]}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1", "items": []}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1", "items": [{"sku": "a1", "qty": 2}]}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1", "items": [{"sku": "A1", "qty": 2.5}]}`).ConformTo(schema)).
+		ShouldNot(it.Json(`{"id": "o1", "items": [{"sku": "A1", "qty": 2, "x": 1}]}`).ConformTo(schema)).
+		ShouldNot(it.Json(`[]`).ConformTo(schema))
+}
+
+func TestJsonSchemaCombinators(t *testing.T) {
+	schema := `{
+		"anyOf": [{"type": "string"}, {"type": "integer"}],
+		"oneOf": [{"type": "integer", "minimum": 0}, {"type": "string"}, {"type": "integer", "maximum": -10}],
+		"allOf": [{"not-supported": true}, true]
+	}`
+
+	it.Then(t).
+		Should(it.Json(`"x"`).ConformTo(schema)).
+		Should(it.Json(`5`).ConformTo(schema)).
+		ShouldNot(it.Json(`-5`).ConformTo(schema)).
+		ShouldNot(it.Json(`1.5`).ConformTo(schema)).
+		ShouldNot(it.Json(`5`).ConformTo(`false`)).
+		Should(it.Json(`5`).ConformTo(`true`))
+}
+
+func TestJsonSchemaViolations(t *testing.T) {
+	err := it.Json(`{"id": "o", "items": [{"sku": "a1", "qty": 20, "x": 1}, {"qty": 1}]}`).ConformTo(schema)
+
+	it.Then(t).
+		ShouldNot(err).
+		Should(it.Text(err.Error()).Equal(strings.Join([]string{
+			"conform to schema",
+			`	/id: string "o" length be greater or equal to 2`,
+			`	/items/0/qty: 20 be less or equal to 10`,
+			`	/items/0/sku: string "a1" match ^[A-Z][0-9]+$`,
+			`	/items/0/x: key x is not allowed by additionalProperties`,
+			`	/items/1: object have required key sku`,
+		}, "\n"))).
+		Should(it.String(it.Json(`1`).ConformTo(`{"$ref": "#/$defs/none"}`).Error()).Contain("$ref #/$defs/none be resolvable")).
+		Should(it.String(it.Json(`1`).ConformTo(`{"$ref": "#"}`).Error()).Contain("too deep recursion")).
+		Should(it.String(it.Json(`1`).ConformTo(`{"$ref": "#foo"}`).Error()).Contain("$ref #foo be resolvable: anchors are not supported")).
+		Should(it.String(it.Json(`"x"`).ConformTo(`{"pattern": "(x"}`).Error()).Contain("schema pattern (x be valid regex")).
+		Should(it.String(it.Json(`1`).ConformTo(`{"type": `).Error()).Contain("schema be valid JSON")).
+		Should(it.Equal(it.Json(`{}`).ConformTo(`{"type": ["object", "nul"], "required": [1]}`).Error(), strings.Join([]string{
+			"schema be valid",
+			`	(root): type "nul" be one of null, boolean, object, array, number, integer or string`,
+			`	(root): required [1] be array of strings`,
+		}, "\n"))).
+		Should(it.String(it.Json(`3`).ConformTo(`{"minimum": "1", "maxLength": 1.5}`).Error()).Contain(`minimum "1" be number`))
+}
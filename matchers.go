@@ -9,11 +9,20 @@
 package it
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"math"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 //
@@ -52,6 +61,145 @@ func (x String) Contain(y string) error {
 	return passed(assert)
 }
 
+func (x String) BeEmpty() error {
+	assert := fmt.Errorf("string %q be empty", x)
+
+	if len(x) != 0 {
+		return assert
+	}
+
+	return passed(assert)
+}
+
+func (x String) EqualFold(y string) error {
+	assert := fmt.Errorf("string %s be equal to %s under case folding", x, y)
+
+	if !strings.EqualFold(string(x), y) {
+		return assert
+	}
+
+	return passed(assert)
+}
+
+// HaveLen checks length of string in bytes
+func (x String) HaveLen(n int) error {
+	assert := fmt.Errorf("string %s have length %d", x, n)
+
+	if len(x) != n {
+		return fmt.Errorf("%w, actual length %d", assert, len(x))
+	}
+
+	return passed(assert)
+}
+
+// HaveRuneLen checks length of string in runes (unicode code points)
+func (x String) HaveRuneLen(n int) error {
+	assert := fmt.Errorf("string %s have %d runes", x, n)
+
+	if c := utf8.RuneCountInString(string(x)); c != n {
+		return fmt.Errorf("%w, actual %d runes", assert, c)
+	}
+
+	return passed(assert)
+}
+
+func (x String) ContainAll(ys ...string) error {
+	assert := fmt.Errorf("string %s contain all of %q", x, ys)
+
+	missing := make([]string, 0)
+	for _, y := range ys {
+		if !strings.Contains(string(x), y) {
+			missing = append(missing, y)
+		}
+	}
+
+	if len(missing) != 0 {
+		return fmt.Errorf("%w, missing %q", assert, missing)
+	}
+
+	return passed(assert)
+}
+
+func (x String) ContainAny(ys ...string) error {
+	assert := fmt.Errorf("string %s contain any of %q", x, ys)
+
+	for _, y := range ys {
+		if strings.Contains(string(x), y) {
+			return passed(assert)
+		}
+	}
+
+	return assert
+}
+
+// Count checks number of non-overlapping instances of substr
+func (x String) Count(substr string, n int) error {
+	assert := fmt.Errorf("string %s contain %q %d times", x, substr, n)
+
+	if c := strings.Count(string(x), substr); c != n {
+		return fmt.Errorf("%w, actual %d times", assert, c)
+	}
+
+	return passed(assert)
+}
+
+// Match checks string against regular expression
+//
+//	it.Should(it.String(x).Match(`v(?P<major>\d+)`))
+//	it.Should(it.String(x).Match(`v(?P<major>\d+)`).With("major", "2"))
+func (x String) Match(pattern string) StringMatchIt {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return StringMatchIt{assert: invalidInput(fmt.Errorf("pattern %s be valid regex: %w", pattern, err))}
+	}
+
+	assert := fmt.Errorf("string %s match %s", x, pattern)
+	match := re.FindStringSubmatch(string(x))
+	if match == nil {
+		return StringMatchIt{assert: assert}
+	}
+
+	groups := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if i != 0 && name != "" {
+			groups[name] = match[i]
+		}
+	}
+
+	return StringMatchIt{assert: passed(assert), groups: groups}
+}
+
+// StringMatchIt extend Match assert
+type StringMatchIt struct {
+	assert error
+	groups map[string]string
+}
+
+func (x StringMatchIt) Error() string      { return x.assert.Error() }
+func (x StringMatchIt) As(target any) bool { return errors.As(x.assert, target) }
+
+// With asserts named capture group of matched regular expression
+//
+//	it.Should(it.String(x).Match(`v(?P<major>\d+)`).With("major", "2"))
+func (x StringMatchIt) With(name, y string) error {
+	if isInvalid(x.assert) {
+		return x.assert
+	}
+
+	assert := fmt.Errorf("%s with group %s equal to %s", x.assert, name, y)
+
+	v, has := x.groups[name]
+	if !has {
+		return assert
+	}
+
+	if v != y {
+		return fmt.Errorf("%w, actual %s", assert, v)
+	}
+
+	return passed(assert)
+}
+
 //
 // Sequence of elements
 //
@@ -86,156 +234,1000 @@ func (xs SeqOf[A]) Equal(ys ...A) error {
 	return passed(fmt.Errorf("seq %v is equal to %v", xs, ys))
 }
 
-func (xs SeqOf[A]) Contain(ys ...A) SeqContainIt[A] {
-	assert := fmt.Errorf("seq %v contain %v", xs, ys)
+func (xs SeqOf[A]) HaveLen(n int) error {
+	assert := fmt.Errorf("seq %v have length %d", xs, n)
 
-	for _, y := range ys {
-		has := false
-		for _, x := range xs {
-			if equal(x, y) {
-				has = true
+	if len(xs) != n {
+		return fmt.Errorf("%w, actual length %d", assert, len(xs))
+	}
+
+	return passed(assert)
+}
+
+// BeSorted checks that sequence is ordered by the less function
+//
+//	it.Should(it.Seq(xs).BeSorted(func(a, b T) bool { return a.ID < b.ID }))
+func (xs SeqOf[A]) BeSorted(less func(a, b A) bool) error {
+	assert := fmt.Errorf("seq %v be sorted", xs)
+
+	for i := 1; i < len(xs); i++ {
+		if less(xs[i], xs[i-1]) {
+			return fmt.Errorf("%w, %dth element %v is out of order with %dth element %v", assert, i, xs[i], i-1, xs[i-1])
+		}
+	}
+
+	return passed(assert)
+}
+
+// BeSortedFunc checks that sequence is ordered by the comparator, which
+// follows the contract of slices.SortFunc.
+//
+//	it.Should(it.Seq(xs).BeSortedFunc(func(a, b T) int { return cmp.Compare(a.ID, b.ID) }))
+func (xs SeqOf[A]) BeSortedFunc(compare func(a, b A) int) error {
+	return xs.BeSorted(func(a, b A) bool { return compare(a, b) < 0 })
+}
+
+// SortedBy checks that sequence is ordered by the key extracted from elements.
+// Methods do not have type parameters, the assert is the package function.
+//
+//	it.Should(it.SortedBy(xs, func(x T) int { return x.ID }))
+func SortedBy[A any, K cmp.Ordered](xs []A, key func(A) K) error {
+	return Seq(xs).BeSorted(func(a, b A) bool { return key(a) < key(b) })
+}
+
+func (xs SeqOf[A]) BeUnique() error {
+	assert := fmt.Errorf("seq %v be unique", xs)
+
+	for i, j := range xs.duplicates() {
+		if j != -1 {
+			return fmt.Errorf("%w, %dth element %v duplicates %dth element", assert, i, xs[i], j)
+		}
+	}
+
+	return passed(assert)
+}
+
+func (xs SeqOf[A]) HaveDuplicates() error {
+	assert := fmt.Errorf("seq %v have duplicates", xs)
+
+	for _, j := range xs.duplicates() {
+		if j != -1 {
+			return passed(assert)
+		}
+	}
+
+	return assert
+}
+
+// duplicates maps each element to index of its first occurrence, -1 if none
+func (xs SeqOf[A]) duplicates() []int {
+	seq := make([]int, len(xs))
+	for i := range xs {
+		seq[i] = -1
+		for j := 0; j < i; j++ {
+			if equal(xs[i], xs[j]) {
+				seq[i] = j
 				break
 			}
 		}
-		if !has {
-			return SeqContainIt[A]{assert, xs}
+	}
+	return seq
+}
+
+func (xs SeqOf[A]) StartWith(ys ...A) error {
+	assert := fmt.Errorf("seq %v start with %v", xs, ys)
+
+	if len(xs) < len(ys) {
+		return fmt.Errorf("%w, seq is shorter than prefix", assert)
+	}
+
+	for i, y := range ys {
+		if !equal(xs[i], y) {
+			return fmt.Errorf("%w, %dth element %v be equal to %v", assert, i, xs[i], y)
+		}
+	}
+
+	return passed(assert)
+}
+
+func (xs SeqOf[A]) EndWith(ys ...A) error {
+	assert := fmt.Errorf("seq %v end with %v", xs, ys)
+
+	if len(xs) < len(ys) {
+		return fmt.Errorf("%w, seq is shorter than suffix", assert)
+	}
+
+	off := len(xs) - len(ys)
+	for i, y := range ys {
+		if !equal(xs[off+i], y) {
+			return fmt.Errorf("%w, %dth element %v be equal to %v", assert, off+i, xs[off+i], y)
+		}
+	}
+
+	return passed(assert)
+}
+
+// ContainInOrder checks that sequence contains elements as subsequence,
+// elements must appear in the same order but not necessary adjacent.
+//
+//	it.Should(it.Seq(xs).ContainInOrder(a, c))
+func (xs SeqOf[A]) ContainInOrder(ys ...A) error {
+	assert := fmt.Errorf("seq %v contain in order %v", xs, ys)
+
+	// prev is index of previous matched element
+	prev := -1
+	for i, y := range ys {
+		at := prev + 1
+		for at < len(xs) && !equal(xs[at], y) {
+			at++
+		}
+		if at == len(xs) {
+			if prev == -1 {
+				return fmt.Errorf("%w, %dth expected element %v not found", assert, i, y)
+			}
+			return fmt.Errorf("%w, %dth expected element %v not found after index %d", assert, i, y, prev)
+		}
+		prev = at
+	}
+
+	return passed(assert)
+}
+
+// EqualAnyOrder checks that sequences are equal as multisets, elements
+// might appear in any order but duplicates are counted.
+//
+//	it.Should(it.Seq(xs).EqualAnyOrder(c, a, b))
+func (xs SeqOf[A]) EqualAnyOrder(ys ...A) error {
+	assert := fmt.Errorf("seq %v be equal in any order to %v", xs, ys)
+
+	expect := newBag[A]()
+	for _, y := range ys {
+		expect.add(y)
+	}
+
+	unexpected := newBag[A]()
+	for _, x := range xs {
+		if !expect.remove(x) {
+			unexpected.add(x)
+		}
+	}
+
+	missing := expect.String()
+	extra := unexpected.String()
+
+	switch {
+	case missing != "" && extra != "":
+		return fmt.Errorf("%w, missing %s, unexpected %s", assert, missing, extra)
+	case missing != "":
+		return fmt.Errorf("%w, missing %s", assert, missing)
+	case extra != "":
+		return fmt.Errorf("%w, unexpected %s", assert, extra)
+	}
+
+	return passed(assert)
+}
+
+// bag is multiset of elements, it uses hash index for types comparable by
+// value and deep equality for other types.
+type bag[A any] struct {
+	index map[any]int
+	elems []A
+	count []int
+}
+
+func newBag[A any]() *bag[A] {
+	b := &bag[A]{}
+	if isComparableByValue(reflect.TypeFor[A]()) {
+		b.index = make(map[any]int)
+	}
+	return b
+}
+
+func (b *bag[A]) find(x A) int {
+	if b.index != nil {
+		if i, has := b.index[any(x)]; has {
+			return i
+		}
+		return -1
+	}
+
+	for i, e := range b.elems {
+		if equal(e, x) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *bag[A]) add(x A) {
+	if i := b.find(x); i != -1 {
+		b.count[i]++
+		return
+	}
+
+	if b.index != nil {
+		b.index[any(x)] = len(b.elems)
+	}
+	b.elems = append(b.elems, x)
+	b.count = append(b.count, 1)
+}
+
+func (b *bag[A]) remove(x A) bool {
+	if i := b.find(x); i != -1 && b.count[i] > 0 {
+		b.count[i]--
+		return true
+	}
+	return false
+}
+
+func (b *bag[A]) String() string {
+	seq := make([]string, 0)
+	for i, e := range b.elems {
+		switch {
+		case b.count[i] == 1:
+			seq = append(seq, fmt.Sprintf("%v", e))
+		case b.count[i] > 1:
+			seq = append(seq, fmt.Sprintf("%v (x%d)", e, b.count[i]))
+		}
+	}
+
+	if len(seq) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(seq, " ") + "]"
+}
+
+// isComparableByValue checks if == is equivalent to deep equality for the type
+func isComparableByValue(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return isComparableByValue(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isComparableByValue(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// All checks that every element satisfies the assert
+//
+//	it.Should(it.Seq(xs).All(func(x T) error { return it.Greater(x.Age, 18) }))
+func (xs SeqOf[A]) All(f func(A) error) error {
+	assert := fmt.Errorf("seq %v all elements satisfy", xs)
+
+	_, failed, invalid := xs.quantify(f)
+	switch {
+	case invalid:
+		return invalidInput(fmt.Errorf("%w, failed%s", assert, failed))
+	case len(failed) != 0:
+		return fmt.Errorf("%w, failed%s", assert, failed)
+	}
+
+	return passed(assert)
+}
+
+// Any checks that at least one element satisfies the assert
+//
+//	it.Should(it.Seq(xs).Any(func(x T) error { return it.Equal(x.Name, "bob") }))
+func (xs SeqOf[A]) Any(f func(A) error) error {
+	assert := fmt.Errorf("seq %v any element satisfy", xs)
+
+	ok, failed, invalid := xs.quantify(f)
+	switch {
+	case invalid:
+		return invalidInput(fmt.Errorf("%w, failed%s", assert, failed))
+	case len(ok) == 0:
+		return fmt.Errorf("%w, failed%s", assert, failed)
+	}
+
+	return passed(assert)
+}
+
+// None checks that no element satisfies the assert
+//
+//	it.Should(it.Seq(xs).None(func(x T) error { return it.Nil(x.Err) }))
+func (xs SeqOf[A]) None(f func(A) error) error {
+	assert := fmt.Errorf("seq %v none of elements satisfy", xs)
+
+	ok, failed, invalid := xs.quantify(f)
+	switch {
+	case invalid:
+		return invalidInput(fmt.Errorf("%w, failed%s", assert, failed))
+	case len(ok) != 0:
+		return fmt.Errorf("%w, satisfied%s", assert, ok)
+	}
+
+	return passed(assert)
+}
+
+// Exactly checks that exactly n elements satisfy the assert
+//
+//	it.Should(it.Seq(xs).Exactly(2, func(x T) error { return it.True(x.Admin) }))
+func (xs SeqOf[A]) Exactly(n int, f func(A) error) error {
+	return xs.cardinality(fmt.Sprintf("exactly %d", n), f, func(c int) bool { return c == n })
+}
+
+// AtLeast checks that n or more elements satisfy the assert
+func (xs SeqOf[A]) AtLeast(n int, f func(A) error) error {
+	return xs.cardinality(fmt.Sprintf("at least %d", n), f, func(c int) bool { return c >= n })
+}
+
+// AtMost checks that n or less elements satisfy the assert
+func (xs SeqOf[A]) AtMost(n int, f func(A) error) error {
+	return xs.cardinality(fmt.Sprintf("at most %d", n), f, func(c int) bool { return c <= n })
+}
+
+func (xs SeqOf[A]) cardinality(q string, f func(A) error, pred func(int) bool) error {
+	assert := fmt.Errorf("seq %v %s elements satisfy", xs, q)
+
+	ok, failed, invalid := xs.quantify(f)
+	switch {
+	case invalid:
+		return invalidInput(fmt.Errorf("%w, actual %d\nsatisfied%s\nfailed%s", assert, len(ok), ok, failed))
+	case !pred(len(ok)):
+		return fmt.Errorf("%w, actual %d\nsatisfied%s\nfailed%s", assert, len(ok), ok, failed)
+	}
+
+	return passed(assert)
+}
+
+// quantify evaluates assert on each element, splitting results on passed and
+// failed, the flag reports invalid input of element assert (e.g. malformed
+// regex), which fails the quantifier regardless of the keyword polarity.
+func (xs SeqOf[A]) quantify(f func(A) error) (elementsOf, elementsOf, bool) {
+	ok := make(elementsOf, 0)
+	failed := make(elementsOf, 0)
+	invalid := false
+
+	for i, x := range xs {
+		err := nested(fmt.Errorf("%dth element %v", i, x), f(x))
+		switch {
+		case isInvalid(err):
+			invalid = true
+			failed = append(failed, err)
+		case IsPassed(err):
+			ok = append(ok, err)
+		default:
+			failed = append(failed, err)
 		}
 	}
 
-	return SeqContainIt[A]{passed(assert), xs}
+	return ok, failed, invalid
+}
+
+// elementsOf is results of assert evaluated on elements of sequence
+type elementsOf []error
+
+func (seq elementsOf) String() string {
+	if len(seq) == 0 {
+		return " none"
+	}
+
+	var sb strings.Builder
+	for _, e := range seq {
+		sb.WriteString("\n\t" + e.Error())
+	}
+	return sb.String()
+}
+
+// Contain checks that sequence contains all of elements, same as ContainAll
+//
+//	it.Should(it.Seq(xs).Contain(a, b))
+func (xs SeqOf[A]) Contain(ys ...A) SeqContainIt[A] {
+	return SeqContainIt[A]{xs.ContainAll(ys...), xs}
 }
 
-// FailIt extend Fail assert
+// SeqContainIt extend Contain assert
 type SeqContainIt[A any] struct {
 	assert error
-	xs     []A
+	xs     SeqOf[A]
 }
 
 func (x SeqContainIt[A]) Error() string      { return x.assert.Error() }
 func (x SeqContainIt[A]) As(target any) bool { return errors.As(x.assert, target) }
 
-func (x SeqContainIt[A]) AllOf(ys ...A) error {
-	assert := fmt.Errorf("seq %v contain all of %v", x.xs, ys)
+// Deprecated: use SeqOf.ContainAll, AllOf ignores arguments of Contain
+func (x SeqContainIt[A]) AllOf(ys ...A) error { return x.xs.ContainAll(ys...) }
+
+// Deprecated: use SeqOf.ContainAny, OneOf ignores arguments of Contain
+func (x SeqContainIt[A]) OneOf(ys ...A) error { return x.xs.ContainAny(ys...) }
+
+// ContainAll checks that every element of ys belongs to the sequence,
+// the failure reports missing elements.
+//
+//	it.Should(it.Seq(xs).ContainAll(a, b))
+func (xs SeqOf[A]) ContainAll(ys ...A) error {
+	assert := fmt.Errorf("seq %v contain all of %v", xs, ys)
+
+	if missing := difference(ys, xs); len(missing) != 0 {
+		return fmt.Errorf("%w, missing %v", assert, missing)
+	}
+
+	return passed(assert)
+}
+
+// ContainAny checks that at least one element of ys belongs to the sequence.
+//
+//	it.Should(it.Seq(xs).ContainAny(a, b))
+func (xs SeqOf[A]) ContainAny(ys ...A) error {
+	assert := fmt.Errorf("seq %v contain any of %v", xs, ys)
+
+	if found := intersection(ys, xs); len(found) == 0 {
+		return fmt.Errorf("%w, none found", assert)
+	}
+
+	return passed(assert)
+}
+
+// ContainNone checks that no element of ys belongs to the sequence,
+// the failure reports found elements.
+//
+//	it.Should(it.Seq(xs).ContainNone(a, b))
+func (xs SeqOf[A]) ContainNone(ys ...A) error {
+	assert := fmt.Errorf("seq %v contain none of %v", xs, ys)
+
+	if found := intersection(ys, xs); len(found) != 0 {
+		return fmt.Errorf("%w, found %v", assert, found)
+	}
+
+	return passed(assert)
+}
+
+// ContainExactly checks that the sequence and ys are equal as sets, elements
+// might appear in any order, duplicates are ignored. Use EqualAnyOrder to
+// count duplicates.
+//
+//	it.Should(it.Seq(xs).ContainExactly(a, b))
+func (xs SeqOf[A]) ContainExactly(ys ...A) error {
+	assert := fmt.Errorf("seq %v contain exactly %v", xs, ys)
+
+	missing := difference(ys, xs)
+	extra := difference(xs, ys)
+
+	switch {
+	case len(missing) != 0 && len(extra) != 0:
+		return fmt.Errorf("%w, missing %v, unexpected %v", assert, missing, extra)
+	case len(missing) != 0:
+		return fmt.Errorf("%w, missing %v", assert, missing)
+	case len(extra) != 0:
+		return fmt.Errorf("%w, unexpected %v", assert, extra)
+	}
+
+	return passed(assert)
+}
+
+// BeSubsetOf checks that every element of the sequence belongs to ys,
+// the failure reports unexpected elements.
+//
+//	it.Should(it.Seq(xs).BeSubsetOf(a, b, c))
+func (xs SeqOf[A]) BeSubsetOf(ys ...A) error {
+	assert := fmt.Errorf("seq %v be subset of %v", xs, ys)
+
+	if extra := difference(xs, ys); len(extra) != 0 {
+		return fmt.Errorf("%w, unexpected %v", assert, extra)
+	}
+
+	return passed(assert)
+}
+
+// BeSupersetOf checks that every element of ys belongs to the sequence,
+// the failure reports missing elements.
+//
+//	it.Should(it.Seq(xs).BeSupersetOf(a, b))
+func (xs SeqOf[A]) BeSupersetOf(ys ...A) error {
+	assert := fmt.Errorf("seq %v be superset of %v", xs, ys)
+
+	if missing := difference(ys, xs); len(missing) != 0 {
+		return fmt.Errorf("%w, missing %v", assert, missing)
+	}
+
+	return passed(assert)
+}
+
+// difference returns distinct elements of xs that do not belong to ys
+func difference[A any](xs, ys []A) []A {
+	return distinct(xs, ys, false)
+}
+
+// intersection returns distinct elements of xs that belong to ys
+func intersection[A any](xs, ys []A) []A {
+	return distinct(xs, ys, true)
+}
 
+func distinct[A any](xs, ys []A, member bool) []A {
+	set := newBag[A]()
 	for _, y := range ys {
-		has := false
-		for _, x := range x.xs {
-			if equal(x, y) {
-				has = true
-				break
-			}
+		set.add(y)
+	}
+
+	seen := newBag[A]()
+	seq := make([]A, 0)
+	for _, x := range xs {
+		if (set.find(x) != -1) == member && seen.find(x) == -1 {
+			seen.add(x)
+			seq = append(seq, x)
 		}
-		if !has {
-			return assert
+	}
+	return seq
+}
+
+//
+// Map of elements
+//
+
+type MapOf[K comparable, V any] map[K]V
+
+func Map[K comparable, V any](val map[K]V) MapOf[K, V] {
+	return MapOf[K, V](val)
+}
+
+func (xs MapOf[K, V]) Have(key K, y V) error {
+	x, exists := xs[key]
+	if !exists {
+		return fmt.Errorf("map %v have key %v", xs, key)
+	}
+
+	assert := fmt.Errorf("key %v value %v of %T be equal to %v", key, x, (map[K]V)(xs), y)
+
+	if !equal(x, y) {
+		return assert
+	}
+
+	return passed(assert)
+}
+
+func (xs MapOf[K, V]) HaveKey(key K) error {
+	assert := fmt.Errorf("map %v have key %v", xs, key)
+
+	if _, exists := xs[key]; !exists {
+		return assert
+	}
+
+	return passed(assert)
+}
+
+func (xs MapOf[K, V]) NotHaveKey(key K) error {
+	assert := fmt.Errorf("map %v not have key %v", xs, key)
+
+	if _, exists := xs[key]; exists {
+		return assert
+	}
+
+	return passed(assert)
+}
+
+func (xs MapOf[K, V]) HaveKeys(keys ...K) error {
+	assert := fmt.Errorf("map %v have keys %v", xs, keys)
+
+	missing := make([]K, 0)
+	for _, key := range keys {
+		if _, exists := xs[key]; !exists {
+			missing = append(missing, key)
 		}
 	}
 
+	if len(missing) != 0 {
+		return fmt.Errorf("%w, missing %v", assert, missing)
+	}
+
 	return passed(assert)
 }
 
-func (x SeqContainIt[A]) OneOf(ys ...A) error {
-	assert := fmt.Errorf("seq %v contain one of %v", x.xs, ys)
+func (xs MapOf[K, V]) HaveLen(n int) error {
+	assert := fmt.Errorf("map %v have length %d", xs, n)
 
-	for _, y := range ys {
-		for _, x := range x.xs {
-			if equal(x, y) {
-				return passed(assert)
-			}
+	if len(xs) != n {
+		return fmt.Errorf("%w, actual length %d", assert, len(xs))
+	}
+
+	return passed(assert)
+}
+
+func (xs MapOf[K, V]) ContainValue(y V) error {
+	assert := fmt.Errorf("map %v contain value %v", xs, y)
+
+	for _, x := range xs {
+		if equal(x, y) {
+			return passed(assert)
 		}
 	}
 
 	return assert
 }
 
+// HaveWith applies the assert to the value of key
+//
+//	it.Should(it.Map(xs).HaveWith("age", func(x int) error { return it.Greater(x, 18) }))
+func (xs MapOf[K, V]) HaveWith(key K, f func(V) error) error {
+	x, exists := xs[key]
+	if !exists {
+		return fmt.Errorf("map %v have key %v", xs, key)
+	}
+
+	return nested(fmt.Errorf("key %v value %v of %T", key, x, (map[K]V)(xs)), f(x))
+}
+
+// BeSubsetOf checks that every key of map exists at ys with equal value
+//
+//	it.Should(it.Map(xs).BeSubsetOf(ys))
+func (xs MapOf[K, V]) BeSubsetOf(ys map[K]V) error {
+	assert := fmt.Errorf("map %v be subset of %v", xs, ys)
+
+	d := diffMapOf(xs, ys)
+	d.missing = nil
+
+	if !d.isEmpty() {
+		return fmt.Errorf("%w\n%s", assert, d)
+	}
+
+	return passed(assert)
+}
+
+// Equal checks equality of maps, the failure reports missing, unexpected and
+// changed keys in deterministic order.
+//
+//	it.Should(it.Map(xs).Equal(ys))
+func (xs MapOf[K, V]) Equal(ys map[K]V) error {
+	assert := fmt.Errorf("map %v be equal to %v", xs, ys)
+
+	if d := diffMapOf(xs, ys); !d.isEmpty() {
+		return fmt.Errorf("%w\n%s", assert, d)
+	}
+
+	return passed(assert)
+}
+
+// mapDiff is key-level difference of actual map against expected one
+type mapDiff[K comparable, V any] struct {
+	actual, expect map[K]V
+	missing        []K
+	extra          []K
+	changed        []K
+}
+
+func diffMapOf[K comparable, V any](actual, expect map[K]V) mapDiff[K, V] {
+	d := mapDiff[K, V]{actual: actual, expect: expect}
+
+	for k, x := range actual {
+		y, exists := expect[k]
+		switch {
+		case !exists:
+			d.extra = append(d.extra, k)
+		case !equal(x, y):
+			d.changed = append(d.changed, k)
+		}
+	}
+
+	for k := range expect {
+		if _, exists := actual[k]; !exists {
+			d.missing = append(d.missing, k)
+		}
+	}
+
+	sortKeys(d.missing)
+	sortKeys(d.extra)
+	sortKeys(d.changed)
+
+	return d
+}
+
+func (d mapDiff[K, V]) isEmpty() bool {
+	return len(d.missing) == 0 && len(d.extra) == 0 && len(d.changed) == 0
+}
+
+func (d mapDiff[K, V]) String() string {
+	var sb strings.Builder
+	for _, k := range d.missing {
+		sb.WriteString(fmt.Sprintf("\t- %v: %v (missing)\n", k, d.expect[k]))
+	}
+	for _, k := range d.extra {
+		sb.WriteString(fmt.Sprintf("\t+ %v: %v (unexpected)\n", k, d.actual[k]))
+	}
+	for _, k := range d.changed {
+		sb.WriteString(fmt.Sprintf("\t~ %v: %v => %v (changed)\n", k, d.expect[k], d.actual[k]))
+	}
+	return sb.String()
+}
+
+// sortKeys orders keys of numeric and string kinds by value, other keys by
+// their string representation
+func sortKeys[K comparable](keys []K) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareKeys(keys[i], keys[j]) < 0
+	})
+}
+
+func compareKeys(x, y any) int {
+	a, b := reflect.ValueOf(x), reflect.ValueOf(y)
+	if a.IsValid() && b.IsValid() && a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(a.Int(), b.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(a.Uint(), b.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(a.Float(), b.Float())
+		case reflect.String:
+			return cmp.Compare(a.String(), b.String())
+		}
+	}
+
+	return cmp.Compare(fmt.Sprint(x), fmt.Sprint(y))
+}
+
+//
+// Json
+//
+
+// JsonOf is JSON matcher. The input is either any Go value, which is
+// serialized to JSON, or JSON document given as []byte, json.RawMessage,
+// string or io.Reader.
+type JsonOf[A any] struct {
+	raw      []byte
+	err      error
+	bind     any
+	matchers map[string]any
+}
+
+func Json[A any](obj A) JsonOf[A] {
+	switch v := any(obj).(type) {
+	case json.RawMessage:
+		return JsonOf[A]{raw: v}
+	case []byte:
+		return JsonOf[A]{raw: v}
+	case string:
+		return JsonOf[A]{raw: []byte(v)}
+	case io.Reader:
+		raw, err := io.ReadAll(v)
+		return JsonOf[A]{raw: raw, err: err}
+	default:
+		raw, err := json.Marshal(obj)
+		return JsonOf[A]{raw: raw, err: err}
+	}
+}
+
+// Bind captures variables of patterns "{{name}}" and "$name:pattern" into the
+// target, either map[string]any or pointer to struct (fields are decoded
+// using encoding/json). Values already defined by the map are asserted.
+//
+//	vars := map[string]any{}
+//	it.Should(it.Json(resp).Bind(vars).Equiv(`{"id": "{{id}}"}`))
+func (obj JsonOf[A]) Bind(target any) JsonOf[A] {
+	obj.bind = target
+	return obj
+}
+
+// Using registers reusable matcher, which is referenced by patterns as
+// "match:name". The matcher is either Matcher[T] (e.g. it.GreaterThan(0.0))
+// or assert function func(T) error, the JSON value is decoded to T.
 //
-// Map of elements
-//
-
-type MapOf[K comparable, V any] map[K]V
-
-func Map[K comparable, V any](val map[K]V) MapOf[K, V] {
-	return MapOf[K, V](val)
+//	it.Should(it.Json(obj).Using("price", it.GreaterThan(0.0)).Equiv(`{"price": "match:price"}`))
+func (obj JsonOf[A]) Using(name string, matcher any) JsonOf[A] {
+	matchers := make(map[string]any, len(obj.matchers)+1)
+	for k, v := range obj.matchers {
+		matchers[k] = v
+	}
+	matchers[name] = matcher
+	obj.matchers = matchers
+	return obj
 }
 
-func (xs MapOf[K, V]) Have(key K, y V) error {
-	x, exists := xs[key]
-	if !exists {
-		return fmt.Errorf("map %v have key %v", xs, key)
+// value decodes the input, the malformed input fails the assert regardless
+// of the keyword polarity
+func (obj JsonOf[A]) value() (any, error) {
+	if obj.err != nil {
+		return nil, invalidInput(fmt.Errorf("input be valid JSON: %w", obj.err))
 	}
 
-	assert := fmt.Errorf("key %v value %v of %T be equal to %v", key, x, (map[K]V)(xs), y)
-
-	if !equal(x, y) {
-		return assert
+	var val any
+	if err := json.Unmarshal(obj.raw, &val); err != nil {
+		return nil, invalidInput(invalidJSON("input", obj.raw, err))
 	}
 
-	return passed(assert)
+	return val, nil
 }
 
+// Equiv matches input against patterns, only values defined by the pattern
+// are matched, extra object keys are ignored. Arrays are matched element-wise,
+// missing and extra elements fail unless the pattern ends with "...".
 //
-// Json
-//
-
-type JsonOf[A any] struct{ obj A }
+//	it.Should(it.Json(obj).Equiv(`{"foo": "bar"}`))
+func (obj JsonOf[A]) Equiv(shapes ...string) error {
+	return obj.match(&jsonMatch{}, shapes)
+}
 
-func Json[A any](obj A) JsonOf[A] {
-	return JsonOf[A]{obj: obj}
+// Equal matches input against patterns strictly, in addition to Equiv it
+// fails on extra object keys. The object key "..." allows extra keys locally.
+//
+//	it.Should(it.Json(obj).Equal(`{"foo": "bar", "meta": {"...": "_"}}`))
+func (obj JsonOf[A]) Equal(shapes ...string) error {
+	return obj.match(&jsonMatch{strict: true}, shapes)
 }
 
-func (obj JsonOf[A]) Equiv(shapes ...string) error {
-	var val any
-	raw, err := json.Marshal(obj.obj)
+func (obj JsonOf[A]) match(m *jsonMatch, shapes []string) error {
+	val, err := obj.value()
 	if err != nil {
-		return fmt.Errorf("input be valid JSON")
+		return err
+	}
+
+	m.matchers = make(map[string]func(any) error, len(obj.matchers))
+	for name, matcher := range obj.matchers {
+		f, err := matcherFunc(matcher)
+		if err != nil {
+			return invalidInput(fmt.Errorf("matcher %s be valid: %w", name, err))
+		}
+		m.matchers[name] = f
 	}
-	if err := json.Unmarshal([]byte(raw), &val); err != nil {
-		return fmt.Errorf("input be valid JSON")
+
+	m.vars = make(map[string]any)
+	if vars, ok := obj.bind.(map[string]any); ok {
+		for k, v := range vars {
+			m.vars[k] = v
+		}
 	}
 
 	for _, shape := range shapes {
 		var pat any
 		if err := json.Unmarshal([]byte(shape), &pat); err != nil {
-			return fmt.Errorf("pattern be valid JSON")
+			return invalidInput(invalidJSON("pattern", []byte(shape), err))
 		}
 
-		dv := diffVal(pat, val)
+		if err := m.validPattern("", pat); err != nil {
+			return invalidInput(err)
+		}
+
+		dv := m.diffVal(pat, val)
 		if dv != nil {
 			var sb strings.Builder
 			p := newPrinter(&sb)
-			p.print("", dv)
+			p.print("", "", dv)
 
-			return fmt.Errorf("be matching\n%s", sb.String())
+			return fmt.Errorf("be matching\n%s", strings.TrimSuffix(sb.String(), "\n"))
 		}
 	}
 
+	if err := bindVars(obj.bind, m.vars); err != nil {
+		return err
+	}
+
 	return passed(fmt.Errorf("be matching"))
 }
 
+func bindVars(target any, vars map[string]any) error {
+	switch v := target.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		for k, x := range vars {
+			v[k] = x
+		}
+		return nil
+	default:
+		if rv := reflect.ValueOf(target); rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return fmt.Errorf("capture target %T be map[string]any or pointer to struct", target)
+		}
+
+		raw, err := json.Marshal(vars)
+		if err != nil {
+			return fmt.Errorf("captured variables be valid JSON: %w", err)
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return fmt.Errorf("captured variables be decodable to %T: %w", target, err)
+		}
+		return nil
+	}
+}
+
+// invalidJSON reports decoder error with offset and snippet of the document
+func invalidJSON(what string, raw []byte, err error) error {
+	var serr *json.SyntaxError
+	if !errors.As(err, &serr) {
+		return fmt.Errorf("%s be valid JSON: %w", what, err)
+	}
+
+	at := int(serr.Offset)
+	lo, hi := max(at-20, 0), min(at+20, len(raw))
+
+	return fmt.Errorf("%s be valid JSON: %w at offset %d near %q", what, err, at, raw[lo:hi])
+}
+
+// diff is the difference of JSON values, either changed, missing or
+// unexpected value
 type diff struct {
-	expect any // -
-	actual any // +
+	kind   int
+	expect any
+	actual any
+}
+
+const (
+	diffChanged = iota
+	diffMissing
+	diffUnexpected
+)
+
+// diffObj is the difference of objects, keys are properties that differ
+type diffObj map[string]any
+
+// diffSeq is the difference of arrays, elements are annotated with index
+type diffSeq []diffAt
+
+type diffAt struct {
+	index int // index of element, -1 if unordered element is missing
+	diff  any
+}
+
+// jsonMatch is the context of matching JSON value against the pattern
+type jsonMatch struct {
+	strict   bool
+	vars     map[string]any
+	matchers map[string]func(any) error
 }
 
-func diffVal(pat, val any) any {
+var reCapture = regexp.MustCompile(`^(?:\{\{([A-Za-z_][A-Za-z0-9_]*)\}\}|\$([A-Za-z_][A-Za-z0-9_]*):(.*))$`)
+
+func (m *jsonMatch) diffVal(pat, val any) any {
+	if pp, ok := pat.(string); ok && (strings.HasPrefix(pp, "{{") || strings.HasPrefix(pp, "$")) {
+		if c := reCapture.FindStringSubmatch(pp); c != nil {
+			if c[1] != "" {
+				return m.capture(c[1], "_", val)
+			}
+			return m.capture(c[2], c[3], val)
+		}
+	}
+
 	if pp, ok := pat.(string); ok && pp == "_" {
 		return nil
 	}
 
+	if pp, ok := pat.(string); ok && strings.HasPrefix(pp, "_:") {
+		if !isTypeOf(pp[2:], val) {
+			return diff{expect: pat, actual: val}
+		}
+		return nil
+	}
+
+	if pp, ok := pat.(string); ok && strings.HasPrefix(pp, "match:") {
+		f, has := m.matchers[pp[6:]]
+		if !has {
+			return diff{expect: pat, actual: val}
+		}
+		if err := f(val); !IsPassed(err) {
+			return diff{expect: fmt.Sprintf("%s, %s", pp, err), actual: val}
+		}
+		return nil
+	}
+
+	if pp, ok := pat.(string); ok && strings.HasPrefix(pp, "num:") {
+		vv, ok := val.(float64)
+		if !ok {
+			return diff{expect: pat, actual: val}
+		}
+		if match, err := isNumberOf(pp[4:], vv); err != nil || !match {
+			return diff{expect: pat, actual: val}
+		}
+		return nil
+	}
+
 	switch vv := val.(type) {
 	case string:
 		pp, ok := pat.(string)
 		if !ok {
 			return diff{expect: pat, actual: val}
 		}
-		if strings.HasPrefix(pp, "regex:") {
-			re := regexp.MustCompile(pp[6:])
-			if !re.MatchString(vv) {
-				return diff{expect: pp[6:], actual: val}
-			}
-		} else if strings.HasPrefix(pp, "m/") && pp[len(pp)-1] == '/' {
-			re := regexp.MustCompile(pp[2 : len(pp)-1])
-			if !re.MatchString(vv) {
-				return diff{expect: pp[2 : len(pp)-1], actual: val}
+		if expr, isRegex := regexOf(pp); isRegex {
+			re, err := compileRegex(expr)
+			if err != nil || !re.MatchString(vv) {
+				return diff{expect: expr, actual: val}
 			}
 		} else if vv != pp {
 			return diff{expect: pat, actual: val}
@@ -260,33 +1252,39 @@ func diffVal(pat, val any) any {
 			return diff{expect: pat, actual: val}
 		}
 
-		add := make([]any, 0)
-		sub := make([]any, 0)
-		for i, vvx := range vv {
-			if len(pp)-1 < i {
-				sub = append(add, vv[i:])
-				break
+		if len(pp) > 0 {
+			switch pp[0] {
+			case "@unordered":
+				return m.diffSet(pp[1:], vv, true)
+			case "@contains":
+				return m.diffSet(pp[1:], vv, false)
 			}
-			if pp[i] == "..." {
+		}
+
+		seq := make(diffSeq, 0)
+		tail := false
+		for i, p := range pp {
+			if p == "..." {
+				tail = true
 				break
 			}
-			if dv := diffVal(pp[i], vvx); dv != nil {
-				switch v := dv.(type) {
-				case diff:
-					if v.actual != nil {
-						add = append(add, v.actual)
-					}
-					if v.expect != nil {
-						sub = append(sub, v.expect)
-					}
-				case map[string]any:
-					return v
-				}
+			if i >= len(vv) {
+				seq = append(seq, diffAt{index: i, diff: diff{kind: diffMissing, expect: p}})
+				continue
+			}
+			if dv := m.diffVal(p, vv[i]); dv != nil {
+				seq = append(seq, diffAt{index: i, diff: dv})
+			}
+		}
+
+		if !tail {
+			for i := len(pp); i < len(vv); i++ {
+				seq = append(seq, diffAt{index: i, diff: diff{kind: diffUnexpected, actual: vv[i]}})
 			}
 		}
 
-		if len(add) != 0 || len(sub) != 0 {
-			return diff{expect: sub, actual: add}
+		if len(seq) != 0 {
+			return seq
 		}
 
 		return nil
@@ -295,7 +1293,7 @@ func diffVal(pat, val any) any {
 		if !ok {
 			return diff{expect: pat, actual: val}
 		}
-		return diffMap(pp, vv)
+		return m.diffMap(pp, vv)
 	case nil:
 		if pat == nil {
 			return nil
@@ -307,20 +1305,122 @@ func diffVal(pat, val any) any {
 	return nil
 }
 
-func diffMap(pat, val map[string]any) any {
-	d := make(map[string]any)
+// capture binds the variable to value if it matches the pattern, the value of
+// variable bound earlier must be equal.
+func (m *jsonMatch) capture(name string, pat any, val any) any {
+	if dv := m.diffVal(pat, val); dv != nil {
+		return dv
+	}
+
+	if x, has := m.vars[name]; has && !equal(x, val) {
+		return diff{expect: x, actual: val}
+	}
+
+	m.vars[name] = val
+	return nil
+}
+
+// diffSet matches each pattern element to distinct element of array in any
+// order (maximum bipartite matching). The exact requires every element of
+// array to be matched, otherwise array might contain other elements.
+func (m *jsonMatch) diffSet(pat, val []any, exact bool) any {
+	// match[j] is index of pattern matched to j-th element of val
+	match := make([]int, len(val))
+	for j := range match {
+		match[j] = -1
+	}
+
+	// Note: trial matches must not capture variables
+	vars := m.vars
+	fit := make([][]bool, len(pat))
+	for i, p := range pat {
+		fit[i] = make([]bool, len(val))
+		for j, v := range val {
+			m.vars = maps.Clone(vars)
+			fit[i][j] = m.diffVal(p, v) == nil
+		}
+	}
+	m.vars = vars
+
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for j := range val {
+			if fit[i][j] && !seen[j] {
+				seen[j] = true
+				if match[j] == -1 || augment(match[j], seen) {
+					match[j] = i
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	seq := make(diffSeq, 0)
+	for i, p := range pat {
+		if !augment(i, make([]bool, len(val))) {
+			seq = append(seq, diffAt{index: -1, diff: diff{kind: diffMissing, expect: p}})
+		}
+	}
+
+	for j, v := range val {
+		switch {
+		case match[j] != -1:
+			// Note: captures are bound here, the variable captured by other
+			//       element must be equal
+			if dv := m.diffVal(pat[match[j]], v); dv != nil {
+				seq = append(seq, diffAt{index: j, diff: dv})
+			}
+		case exact:
+			seq = append(seq, diffAt{index: j, diff: diff{kind: diffUnexpected, actual: v}})
+		}
+	}
+
+	if len(seq) != 0 {
+		return seq
+	}
+
+	return nil
+}
+
+func (m *jsonMatch) diffMap(pat, val map[string]any) any {
+	d := make(diffObj)
+	_, extra := pat["..."]
 
 	for k, p := range pat {
+		if k == "..." {
+			continue
+		}
+
 		v, has := val[k]
+		if !has && strings.HasSuffix(k, "?") {
+			// optional key is matched only if it exists
+			if v, has = val[k[:len(k)-1]]; !has {
+				continue
+			}
+			k = k[:len(k)-1]
+		}
+
 		if !has {
-			d[k] = diff{expect: p}
+			d[k] = diff{kind: diffMissing, expect: p}
+			continue
 		}
 
-		if dv := diffVal(p, v); dv != nil {
+		if dv := m.diffVal(p, v); dv != nil {
 			d[k] = dv
 		}
 	}
 
+	if m.strict && !extra {
+		for k, v := range val {
+			_, has := pat[k]
+			_, opt := pat[k+"?"]
+			if !has && !opt {
+				d[k] = diff{kind: diffUnexpected, actual: v}
+			}
+		}
+	}
+
 	if len(d) != 0 {
 		return d
 	}
@@ -328,69 +1428,382 @@ func diffMap(pat, val map[string]any) any {
 	return nil
 }
 
+// diffExact is the difference of JSON values without pattern semantic, the
+// strings "_", "regex:...", "..." and others are compared as data.
+func diffExact(expect, val any) any {
+	switch vv := val.(type) {
+	case []any:
+		pp, ok := expect.([]any)
+		if !ok {
+			return diff{expect: expect, actual: val}
+		}
+
+		seq := make(diffSeq, 0)
+		for i := 0; i < max(len(pp), len(vv)); i++ {
+			switch {
+			case i >= len(vv):
+				seq = append(seq, diffAt{index: i, diff: diff{kind: diffMissing, expect: pp[i]}})
+			case i >= len(pp):
+				seq = append(seq, diffAt{index: i, diff: diff{kind: diffUnexpected, actual: vv[i]}})
+			default:
+				if dv := diffExact(pp[i], vv[i]); dv != nil {
+					seq = append(seq, diffAt{index: i, diff: dv})
+				}
+			}
+		}
+
+		if len(seq) != 0 {
+			return seq
+		}
+		return nil
+	case map[string]any:
+		pp, ok := expect.(map[string]any)
+		if !ok {
+			return diff{expect: expect, actual: val}
+		}
+
+		d := make(diffObj)
+		for k, p := range pp {
+			v, has := vv[k]
+			if !has {
+				d[k] = diff{kind: diffMissing, expect: p}
+				continue
+			}
+			if dv := diffExact(p, v); dv != nil {
+				d[k] = dv
+			}
+		}
+		for k, v := range vv {
+			if _, has := pp[k]; !has {
+				d[k] = diff{kind: diffUnexpected, actual: v}
+			}
+		}
+
+		if len(d) != 0 {
+			return d
+		}
+		return nil
+	default:
+		if !equal(expect, val) {
+			return diff{expect: expect, actual: val}
+		}
+		return nil
+	}
+}
+
+// validPattern checks regular expressions, numeric expressions and matchers
+// of the pattern up front, so that typo is reported with its path.
+func (m *jsonMatch) validPattern(path string, pat any) error {
+	switch pp := pat.(type) {
+	case string:
+		if c := reCapture.FindStringSubmatch(pp); c != nil && c[1] == "" {
+			return m.validPattern(path, c[3])
+		}
+
+		var err error
+		if strings.HasPrefix(pp, "_:") {
+			err = validTypeOf(pp[2:])
+		} else if strings.HasPrefix(pp, "num:") {
+			err = validNumberOf(pp[4:])
+		} else if name, isMatcher := strings.CutPrefix(pp, "match:"); isMatcher && m.matchers[name] == nil {
+			err = fmt.Errorf("matcher %s be registered with Using", name)
+		} else if expr, isRegex := regexOf(pp); isRegex {
+			_, err = compileRegex(expr)
+		}
+
+		if err != nil {
+			if path == "" {
+				path = "(root)"
+			}
+			return fmt.Errorf("pattern %q at path %s be valid: %w", pp, path, err)
+		}
+	case []any:
+		for i, p := range pp {
+			if err := m.validPattern(fmt.Sprintf("%s/%d", path, i), p); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(pp))
+		for k := range pp {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if err := m.validPattern(path+"/"+escapeJsonPointer(k), pp[k]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// matcherFunc adapts Matcher[T] or func(T) error to assert of JSON value
+func matcherFunc(matcher any) (func(any) error, error) {
+	f, ok := assertOf(matcher)
+	if !ok {
+		return nil, fmt.Errorf("%T be Matcher[T] or func(T) error", matcher)
+	}
+
+	t := f.Type().In(0)
+	return func(val any) error {
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		x := reflect.New(t)
+		if err := json.Unmarshal(raw, x.Interface()); err != nil {
+			return fmt.Errorf("value %s be decodable to %s: %w", raw, t, err)
+		}
+		err, _ = f.Call([]reflect.Value{x.Elem()})[0].Interface().(error)
+		return err
+	}, nil
+}
+
+// regexOf returns regular expression of patterns "regex:..." and "m/.../"
+func regexOf(pat string) (string, bool) {
+	switch {
+	case strings.HasPrefix(pat, "regex:"):
+		return pat[6:], true
+	case len(pat) >= 3 && strings.HasPrefix(pat, "m/") && strings.HasSuffix(pat, "/"):
+		return pat[2 : len(pat)-1], true
+	default:
+		return "", false
+	}
+}
+
+// regexCache keeps compiled regular expressions of patterns across calls
+var regexCache sync.Map
+
+func compileRegex(expr string) (*regexp.Regexp, error) {
+	if re, has := regexCache.Load(expr); has {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	regexCache.Store(expr, re)
+	return re, nil
+}
+
+// isTypeOf matches JSON value against type wildcard, e.g. string or
+// string|null for union of types
+// validTypeOf checks type names of typed wildcard "_:string|null"
+func validTypeOf(types string) error {
+	for _, t := range strings.Split(types, "|") {
+		switch t {
+		case "string", "number", "bool", "array", "object", "null":
+		default:
+			return fmt.Errorf("type %q be one of string, number, bool, array, object or null", t)
+		}
+	}
+	return nil
+}
+
+func isTypeOf(types string, val any) bool {
+	for _, t := range strings.Split(types, "|") {
+		switch val.(type) {
+		case string:
+			if t == "string" {
+				return true
+			}
+		case float64:
+			if t == "number" {
+				return true
+			}
+		case bool:
+			if t == "bool" {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		case nil:
+			if t == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNumberOf matches number against conjunction of numeric expressions:
+//
+//	>0, >=0, <0, <=0, =0, !=0   comparison
+//	[1,10), (0,1], [1,)        interval, the empty bound is infinity
+//	~3.14±0.01, ~3.14+-0.01    approximation
+//	int                        integer
+//	int&>0                     conjunction
+func isNumberOf(expr string, x float64) (bool, error) {
+	for _, term := range strings.Split(expr, "&") {
+		ok, err := isNumberOfTerm(strings.TrimSpace(term), x)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// validNumberOf checks syntax of every term of numeric expression
+func validNumberOf(expr string) error {
+	for _, term := range strings.Split(expr, "&") {
+		if _, err := isNumberOfTerm(strings.TrimSpace(term), 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isNumberOfTerm(term string, x float64) (bool, error) {
+	num := func(s string) (float64, error) {
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+
+	compare := func(prefix string, f func(float64) bool) (bool, error) {
+		y, err := num(term[len(prefix):])
+		if err != nil {
+			return false, fmt.Errorf("invalid number expression %s", term)
+		}
+		return f(y), nil
+	}
+
+	switch {
+	case term == "int":
+		return x == math.Trunc(x), nil
+	case strings.HasPrefix(term, ">="):
+		return compare(">=", func(y float64) bool { return x >= y })
+	case strings.HasPrefix(term, "<="):
+		return compare("<=", func(y float64) bool { return x <= y })
+	case strings.HasPrefix(term, "!="):
+		return compare("!=", func(y float64) bool { return x != y })
+	case strings.HasPrefix(term, ">"):
+		return compare(">", func(y float64) bool { return x > y })
+	case strings.HasPrefix(term, "<"):
+		return compare("<", func(y float64) bool { return x < y })
+	case strings.HasPrefix(term, "="):
+		return compare("=", func(y float64) bool { return x == y })
+	case strings.HasPrefix(term, "~"):
+		v, eps, found := strings.Cut(term[1:], "±")
+		if !found {
+			v, eps, found = strings.Cut(term[1:], "+-")
+		}
+		if !found {
+			return false, fmt.Errorf("invalid number expression %s", term)
+		}
+		y, err1 := num(v)
+		e, err2 := num(eps)
+		if err1 != nil || err2 != nil {
+			return false, fmt.Errorf("invalid number expression %s", term)
+		}
+		return math.Abs(x-y) <= e, nil
+	case len(term) > 2 && (term[0] == '[' || term[0] == '(') && (term[len(term)-1] == ']' || term[len(term)-1] == ')'):
+		lo, hi, found := strings.Cut(term[1:len(term)-1], ",")
+		if !found {
+			return false, fmt.Errorf("invalid number expression %s", term)
+		}
+		// empty bound is infinity
+		xlo, xhi := 1, -1
+		if strings.TrimSpace(lo) != "" {
+			y, err := num(lo)
+			if err != nil {
+				return false, fmt.Errorf("invalid number expression %s", term)
+			}
+			xlo = cmp.Compare(x, y)
+		}
+		if strings.TrimSpace(hi) != "" {
+			y, err := num(hi)
+			if err != nil {
+				return false, fmt.Errorf("invalid number expression %s", term)
+			}
+			xhi = cmp.Compare(x, y)
+		}
+		return IsPassed(inRange(x, lo, hi, xlo, xhi, term[0] == '(', term[len(term)-1] == ')')), nil
+	default:
+		return false, fmt.Errorf("invalid number expression %s", term)
+	}
+}
+
 //------------------------------------------------------------------------------
 
+// printer renders difference of JSON values. Object keys are sorted, array
+// elements are annotated with index, missing, unexpected and changed values
+// are marked with -, + and ~, so that output is stable.
+//
+//	  {
+//	    "a": {
+//	~     "b": 1 => 2 (changed)
+//	    }
+//	-   "c": "x" (missing)
+//	    "d": [
+//	+     [2]: true (unexpected)
+//	    ]
+//	  }
 type printer struct {
 	sb *strings.Builder
 }
 
 func newPrinter(sb *strings.Builder) printer {
-	sb.WriteString("\x1b[0m")
 	return printer{sb: sb}
 }
 
 func (p printer) atos(v any) string {
-	b, _ := json.Marshal(v)
-	return string(b)
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	return strings.TrimSuffix(sb.String(), "\n")
 }
 
-func (p printer) value(sign, indent string, key string, v any) {
-	p.sb.WriteString(fmt.Sprintf("%s %s", sign, indent))
-	if key != "" {
-		p.sb.WriteString(fmt.Sprintf("\"%s\": ", key))
-	}
-	p.sb.WriteString(p.atos(v))
+func (p printer) line(marker, indent, text string) {
+	p.sb.WriteString(fmt.Sprintf("%s %s%s\n", marker, indent, text))
 }
 
-func (p printer) diff(indent string, key string, v diff) {
-	if v.expect != nil {
-		if v.actual == nil {
-			p.sb.WriteString("\x1b[1;43m")
-		} else {
-			p.sb.WriteString("\x1b[1;33m")
-		}
-		p.value("-", indent, key, v.expect)
-		p.sb.WriteString("\x1b[0m")
-		p.sb.WriteString("\n")
-	}
-
-	if v.actual != nil {
-		p.sb.WriteString("\x1b[1;41m")
-		p.value("+", indent, key, v.actual)
-		p.sb.WriteString("\x1b[0m")
-		p.sb.WriteString("\n")
+func (p printer) diff(indent string, label string, v diff) {
+	switch v.kind {
+	case diffMissing:
+		p.line("-", indent, fmt.Sprintf("%s%s (missing)", label, p.atos(v.expect)))
+	case diffUnexpected:
+		p.line("+", indent, fmt.Sprintf("%s%s (unexpected)", label, p.atos(v.actual)))
+	default:
+		p.line("~", indent, fmt.Sprintf("%s%s => %s (changed)", label, p.atos(v.expect), p.atos(v.actual)))
 	}
 }
 
-func (p printer) print(indent string, val any) {
+func (p printer) print(indent string, label string, val any) {
 	switch obj := val.(type) {
 	case diff:
-		p.diff(indent, "", obj)
-	case map[string]any:
-		p.sb.WriteString("  {\n")
-		for k, v := range obj {
-			switch vv := v.(type) {
-			case diff:
-				p.sb.WriteString("\n")
-				p.diff(indent+"  ", k, vv)
-				p.sb.WriteString("\n")
-			default:
-				p.sb.WriteString(fmt.Sprintf("  %s\"%s\": ", indent+"  ", k))
-				p.print(indent+"  ", v)
+		p.diff(indent, label, obj)
+	case diffObj:
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		p.line(" ", indent, label+"{")
+		for _, k := range keys {
+			p.print(indent+"  ", p.atos(k)+": ", obj[k])
+		}
+		p.line(" ", indent, "}")
+	case diffSeq:
+		p.line(" ", indent, label+"[")
+		for _, e := range obj {
+			at := ""
+			if e.index >= 0 {
+				at = fmt.Sprintf("[%d]: ", e.index)
 			}
-			p.sb.WriteString(fmt.Sprintf("  %s}\n", indent))
+			p.print(indent+"  ", at, e.diff)
 		}
-	default:
-		p.sb.WriteString(p.atos(obj))
+		p.line(" ", indent, "]")
 	}
 }
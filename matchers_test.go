@@ -9,6 +9,9 @@
 package it_test
 
 import (
+	"cmp"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/fogfish/it/v2"
@@ -32,6 +35,52 @@ func TestStringContain(t *testing.T) {
 		ShouldNot(it.String("abcdef").Contain("xxx"))
 }
 
+func TestStringEmpty(t *testing.T) {
+	it.Ok(t).
+		Should(it.String("").BeEmpty()).
+		ShouldNot(it.String("abc").BeEmpty())
+}
+
+func TestStringEqualFold(t *testing.T) {
+	it.Ok(t).
+		Should(it.String("AbcDef").EqualFold("abcdef")).
+		ShouldNot(it.String("abcdef").EqualFold("abc"))
+}
+
+func TestStringLen(t *testing.T) {
+	it.Ok(t).
+		Should(it.String("abc").HaveLen(3)).
+		ShouldNot(it.String("abc").HaveLen(2)).
+		Should(it.String("héllo").HaveLen(6)).
+		Should(it.String("héllo").HaveRuneLen(5)).
+		ShouldNot(it.String("héllo").HaveRuneLen(6))
+}
+
+func TestStringContainAllAny(t *testing.T) {
+	it.Ok(t).
+		Should(it.String("abcdef").ContainAll("ab", "ef")).
+		ShouldNot(it.String("abcdef").ContainAll("ab", "xx")).
+		Should(it.String("abcdef").ContainAny("xx", "ef")).
+		ShouldNot(it.String("abcdef").ContainAny("xx", "yy"))
+}
+
+func TestStringCount(t *testing.T) {
+	it.Ok(t).
+		Should(it.String("abcabc").Count("bc", 2)).
+		ShouldNot(it.String("abcabc").Count("bc", 1))
+}
+
+func TestStringMatch(t *testing.T) {
+	it.Ok(t).
+		Should(it.String("v2.1").Match(`^v\d+`)).
+		ShouldNot(it.String("x2.1").Match(`^v\d+`)).
+		Should(it.String(it.String("v2.1").Match(`^v(\d+`).Error()).Contain("be valid regex")).
+		Should(it.String("v2.1").Match(`^v(?P<major>\d+)\.(?P<minor>\d+)`).With("major", "2")).
+		ShouldNot(it.String("v2.1").Match(`^v(?P<major>\d+)\.(?P<minor>\d+)`).With("minor", "2")).
+		ShouldNot(it.String("v2.1").Match(`^v(?P<major>\d+)`).With("patch", "1")).
+		ShouldNot(it.String("x2.1").Match(`^v(?P<major>\d+)`).With("major", "2"))
+}
+
 func TestSeqEmpty(t *testing.T) {
 	type T struct{ string }
 	seq := []T{{"a"}, {"b"}, {"c"}}
@@ -64,6 +113,165 @@ func TestSeqContain(t *testing.T) {
 		ShouldNot(it.Seq(seq).Contain().OneOf(T{"y"}, T{"x"}))
 }
 
+func TestSeqLen(t *testing.T) {
+	it.Ok(t).
+		Should(it.Seq([]int{1, 2, 3}).HaveLen(3)).
+		ShouldNot(it.Seq([]int{1, 2, 3}).HaveLen(2))
+}
+
+func TestSeqSorted(t *testing.T) {
+	type T struct{ int }
+	less := func(a, b int) bool { return a < b }
+	by := func(a, b T) int { return cmp.Compare(a.int, b.int) }
+
+	it.Ok(t).
+		Should(it.Seq([]int{}).BeSorted(less)).
+		Should(it.Seq([]int{1, 2, 2, 3}).BeSorted(less)).
+		ShouldNot(it.Seq([]int{1, 3, 2}).BeSorted(less)).
+		Should(it.Seq([]T{{1}, {2}, {3}}).BeSortedFunc(by)).
+		ShouldNot(it.Seq([]T{{3}, {2}, {1}}).BeSortedFunc(by)).
+		Should(it.SortedBy([]T{{1}, {2}, {2}}, func(x T) int { return x.int })).
+		ShouldNot(it.SortedBy([]T{{1}, {3}, {2}}, func(x T) int { return x.int }))
+}
+
+func TestSeqUnique(t *testing.T) {
+	type T struct{ string }
+
+	it.Ok(t).
+		Should(it.Seq([]T{{"a"}, {"b"}, {"c"}}).BeUnique()).
+		ShouldNot(it.Seq([]T{{"a"}, {"b"}, {"a"}}).BeUnique()).
+		Should(it.Seq([]T{{"a"}, {"b"}, {"a"}}).HaveDuplicates()).
+		ShouldNot(it.Seq([]T{{"a"}, {"b"}, {"c"}}).HaveDuplicates())
+}
+
+func TestSeqStartEndWith(t *testing.T) {
+	seq := []string{"a", "b", "c"}
+
+	it.Ok(t).
+		Should(it.Seq(seq).StartWith("a", "b")).
+		Should(it.Seq(seq).StartWith()).
+		ShouldNot(it.Seq(seq).StartWith("b")).
+		ShouldNot(it.Seq(seq).StartWith("a", "b", "c", "d")).
+		Should(it.Seq(seq).EndWith("b", "c")).
+		ShouldNot(it.Seq(seq).EndWith("b")).
+		ShouldNot(it.Seq(seq).EndWith("z", "a", "b", "c"))
+}
+
+func TestSeqContainInOrder(t *testing.T) {
+	seq := []string{"a", "b", "c", "d"}
+
+	it.Ok(t).
+		Should(it.Seq(seq).ContainInOrder("a", "c")).
+		Should(it.Seq(seq).ContainInOrder("b", "c", "d")).
+		ShouldNot(it.Seq(seq).ContainInOrder("c", "a")).
+		ShouldNot(it.Seq(seq).ContainInOrder("a", "x"))
+
+	it.Ok(t).
+		Should(it.String(it.Seq(seq).ContainInOrder("c", "a").Error()).Contain("1th expected element a not found after index 2")).
+		Should(it.String(it.Seq(seq).ContainInOrder("x").Error()).Contain("0th expected element x not found"))
+}
+
+func TestSeqEqualAnyOrder(t *testing.T) {
+	type T struct{ string }
+	type P struct{ v *string }
+	a, b := "a", "b"
+
+	it.Ok(t).
+		Should(it.Seq([]int{1, 2, 2, 3}).EqualAnyOrder(2, 3, 1, 2)).
+		ShouldNot(it.Seq([]int{1, 2, 2, 3}).EqualAnyOrder(1, 2, 3)).
+		ShouldNot(it.Seq([]int{1, 2, 3}).EqualAnyOrder(1, 2, 2, 3)).
+		ShouldNot(it.Seq([]int{1, 2, 3}).EqualAnyOrder(1, 2, 4)).
+		Should(it.Seq([]T{{"a"}, {"b"}}).EqualAnyOrder(T{"b"}, T{"a"})).
+		Should(it.Seq([]P{{&a}, {&b}}).EqualAnyOrder(P{&b}, P{&a})).
+		Should(it.Seq([][]int{{1}, {2}}).EqualAnyOrder([]int{2}, []int{1})).
+		ShouldNot(it.Seq([][]int{{1}, {2}}).EqualAnyOrder([]int{2}, []int{3}))
+}
+
+func TestSeqEqualAnyOrderMessage(t *testing.T) {
+	msg := it.Seq([]string{"a", "x", "b", "x"}).EqualAnyOrder("b", "c", "c", "a").Error()
+
+	it.Ok(t).
+		Should(it.String(msg).Contain("missing [c (x2)]")).
+		Should(it.String(msg).Contain("unexpected [x (x2)]"))
+}
+
+func TestSeqQuantifiers(t *testing.T) {
+	type T struct {
+		Name string
+		Age  int
+	}
+	seq := []T{{"a", 10}, {"b", 20}, {"c", 30}}
+	adult := func(x T) error { return it.Greater(x.Age, 18) }
+	aged := func(x T) error { return it.Greater(x.Age, 40) }
+	always := func(x T) error { return nil }
+
+	it.Ok(t).
+		Should(it.Seq(seq).All(always)).
+		ShouldNot(it.Seq(seq).All(adult)).
+		Should(it.Seq(seq).Any(adult)).
+		ShouldNot(it.Seq(seq).Any(aged)).
+		Should(it.Seq(seq).None(aged)).
+		ShouldNot(it.Seq(seq).None(adult)).
+		Should(it.Seq(seq).Exactly(2, adult)).
+		ShouldNot(it.Seq(seq).Exactly(3, adult)).
+		Should(it.Seq(seq).AtLeast(1, adult)).
+		ShouldNot(it.Seq(seq).AtLeast(3, adult)).
+		Should(it.Seq(seq).AtMost(2, adult)).
+		ShouldNot(it.Seq(seq).AtMost(1, adult))
+}
+
+func TestSeqQuantifiersMessage(t *testing.T) {
+	msg := it.Seq([]int{5, 20, 7}).All(func(x int) error { return it.Greater(x, 10) }).Error()
+
+	it.Ok(t).
+		Should(it.String(msg).Contain("0th element 5: 5 be greater than 10")).
+		Should(it.String(msg).Contain("2th element 7: 7 be greater than 10")).
+		ShouldNot(it.String(msg).Contain("1th element"))
+}
+
+func TestSeqContainAll(t *testing.T) {
+	type T struct{ string }
+	seq := []T{{"a"}, {"b"}, {"c"}}
+
+	it.Ok(t).
+		Should(it.Seq(seq).ContainAll(T{"b"}, T{"c"})).
+		Should(it.Seq(seq).ContainAll()).
+		ShouldNot(it.Seq(seq).ContainAll(T{"b"}, T{"x"})).
+		Should(it.Seq(seq).ContainAny(T{"x"}, T{"c"})).
+		ShouldNot(it.Seq(seq).ContainAny(T{"y"}, T{"x"})).
+		Should(it.Seq(seq).ContainNone(T{"y"}, T{"x"})).
+		ShouldNot(it.Seq(seq).ContainNone(T{"x"}, T{"c"}))
+}
+
+func TestSeqContainExactly(t *testing.T) {
+	seq := []string{"a", "b", "a", "c"}
+
+	it.Ok(t).
+		Should(it.Seq(seq).ContainExactly("c", "b", "a")).
+		ShouldNot(it.Seq(seq).ContainExactly("c", "b")).
+		ShouldNot(it.Seq(seq).ContainExactly("c", "b", "a", "d"))
+}
+
+func TestSeqSubset(t *testing.T) {
+	seq := []string{"a", "b"}
+
+	it.Ok(t).
+		Should(it.Seq(seq).BeSubsetOf("a", "b", "c")).
+		ShouldNot(it.Seq(seq).BeSubsetOf("a", "c")).
+		Should(it.Seq(seq).BeSupersetOf("a")).
+		ShouldNot(it.Seq(seq).BeSupersetOf("a", "c"))
+}
+
+func TestSeqContainMessage(t *testing.T) {
+	seq := []string{"a", "b", "c"}
+
+	it.Ok(t).
+		Should(it.String(it.Seq(seq).ContainAll("a", "x", "y", "x").Error()).Contain("missing [x y]")).
+		Should(it.String(it.Seq(seq).ContainNone("a", "x", "c").Error()).Contain("found [a c]")).
+		Should(it.String(it.Seq(seq).ContainExactly("a", "d").Error()).Contain("missing [d], unexpected [b c]")).
+		Should(it.String(it.Seq(seq).BeSubsetOf("a").Error()).Contain("unexpected [b c]"))
+}
+
 func TestMapHave(t *testing.T) {
 	type T struct{ string }
 	set := map[int]T{100: {"a"}, 200: {"b"}, 300: {"c"}}
@@ -75,14 +283,79 @@ func TestMapHave(t *testing.T) {
 		ShouldNot(it.Map(set).Have(200, T{"a"}))
 }
 
+func TestMapKeys(t *testing.T) {
+	set := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	it.Ok(t).
+		Should(it.Map(set).HaveKey("a")).
+		ShouldNot(it.Map(set).HaveKey("x")).
+		Should(it.Map(set).NotHaveKey("x")).
+		ShouldNot(it.Map(set).NotHaveKey("a")).
+		Should(it.Map(set).HaveKeys("a", "c")).
+		ShouldNot(it.Map(set).HaveKeys("a", "x")).
+		Should(it.Map(set).HaveLen(3)).
+		ShouldNot(it.Map(set).HaveLen(2))
+}
+
+func TestMapValues(t *testing.T) {
+	type T struct{ string }
+	set := map[int]T{100: {"a"}, 200: {"b"}}
+
+	it.Ok(t).
+		Should(it.Map(set).ContainValue(T{"a"})).
+		ShouldNot(it.Map(set).ContainValue(T{"x"})).
+		Should(it.Map(set).HaveWith(100, func(x T) error { return it.Equal(x.string, "a") })).
+		ShouldNot(it.Map(set).HaveWith(100, func(x T) error { return it.Equal(x.string, "b") })).
+		ShouldNot(it.Map(set).HaveWith(101, func(x T) error { return nil }))
+}
+
+func TestMapEqual(t *testing.T) {
+	set := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	it.Ok(t).
+		Should(it.Map(set).Equal(map[string]int{"a": 1, "b": 2, "c": 3})).
+		ShouldNot(it.Map(set).Equal(map[string]int{"a": 1, "b": 2})).
+		ShouldNot(it.Map(set).Equal(map[string]int{"a": 1, "b": 2, "c": 4})).
+		Should(it.Map(set).BeSubsetOf(map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})).
+		ShouldNot(it.Map(set).BeSubsetOf(map[string]int{"a": 1, "b": 2})).
+		ShouldNot(it.Map(set).BeSubsetOf(map[string]int{"a": 1, "b": 2, "c": 4}))
+}
+
+func TestMapEqualMessage(t *testing.T) {
+	set := map[string]int{"a": 1, "b": 2, "c": 3, "e": 5}
+	msg := it.Map(set).Equal(map[string]int{"a": 1, "b": 20, "c": 30, "d": 4}).Error()
+
+	it.Ok(t).
+		Should(it.Text(msg).Equal(strings.Join([]string{
+			"map map[a:1 b:2 c:3 e:5] be equal to map[a:1 b:20 c:30 d:4]",
+			"\t- d: 4 (missing)",
+			"\t+ e: 5 (unexpected)",
+			"\t~ b: 20 => 2 (changed)",
+			"\t~ c: 30 => 3 (changed)",
+			"",
+		}, "\n")))
+
+	ids := map[int]string{2: "b", 9: "i", 10: "j"}
+	msg = it.Map(ids).Equal(map[int]string{}).Error()
+
+	it.Ok(t).
+		Should(it.Text(msg).Equal(strings.Join([]string{
+			"map map[2:b 9:i 10:j] be equal to map[]",
+			"\t+ 2: b (unexpected)",
+			"\t+ 9: i (unexpected)",
+			"\t+ 10: j (unexpected)",
+			"",
+		}, "\n")))
+}
+
 func TestJson(t *testing.T) {
 	type S []string
 	type M map[string]any
 	t.Run("Success", func(t *testing.T) {
 		for pat, val := range map[string]any{
 			`null`:                  nil,
-			`"_"`:                   "foo",
-			`"foo"`:                 "foo",
+			`"_"`:                   `"foo"`,
+			`"foo"`:                 `"foo"`,
 			`10`:                    10,
 			`10.33`:                 10.33,
 			`true`:                  true,
@@ -107,14 +380,14 @@ func TestJson(t *testing.T) {
 
 	t.Run("Failed", func(t *testing.T) {
 		for pat, val := range map[string]any{
-			`null`:                  "xfoo",
-			`"foo"`:                 "xfoo",
+			`null`:                  `"xfoo"`,
+			`"foo"`:                 `"xfoo"`,
 			`"bar"`:                 100,
 			`10`:                    100,
 			`10.33`:                 100.33,
-			`100`:                   "100",
+			`100`:                   `"100"`,
 			`true`:                  false,
-			`false`:                 "false",
+			`false`:                 `"false"`,
 			`["foo"]`:               S{"foo", "xbar"},
 			`["foo", "bar"]`:        S{"foo", "xbar"},
 			`["_", "bar"]`:          S{"foo", "xbar"},
@@ -135,3 +408,302 @@ func TestJson(t *testing.T) {
 		}
 	})
 }
+
+func TestJsonRaw(t *testing.T) {
+	type T struct {
+		Foo string `json:"foo"`
+	}
+	doc := `{"foo": "bar", "seq": [1, 2]}`
+
+	it.Then(t).
+		Should(it.Json(doc).Equiv(`{"foo": "bar"}`)).
+		Should(it.Json([]byte(doc)).Equiv(`{"seq": [1, 2]}`)).
+		Should(it.Json(json.RawMessage(doc)).Equiv(`{"foo": "_"}`)).
+		Should(it.Json(strings.NewReader(doc)).Equiv(`{"foo": "bar"}`, `{"seq": [1, "_"]}`)).
+		Should(it.Json(T{"bar"}).Equiv(`{"foo": "bar"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"foo": "baz"}`))
+}
+
+func TestJsonInvalid(t *testing.T) {
+	input := it.Json(`{"foo": "bar",, "seq": [1, 2]}`).Equiv(`{"foo": "bar"}`)
+	pattern := it.Json(`{"foo": "bar"}`).Equiv(`{"foo": bar}`)
+
+	// invalid input and patterns are asserted by TestInvalidInput
+	it.Then(t).
+		Should(it.String(input.Error()).Contain("input be valid JSON")).
+		Should(it.String(input.Error()).Contain("at offset 15")).
+		Should(it.String(input.Error()).Contain(`near "{\"foo\": \"bar\",, \"seq\": [1, 2]}"`)).
+		Should(it.String(pattern.Error()).Contain("pattern be valid JSON")).
+		Should(it.String(pattern.Error()).Contain("at offset 9")).
+		Should(it.String(it.Json(make(chan int)).Equiv(`"_"`).Error()).Contain("input be valid JSON"))
+}
+
+func TestJsonTypeWildcard(t *testing.T) {
+	doc := `{"s": "foo", "n": 10.5, "b": true, "a": [1], "o": {}, "z": null}`
+
+	it.Then(t).
+		Should(it.Json(doc).Equiv(`{
+			"s": "_:string",
+			"n": "_:number",
+			"b": "_:bool",
+			"a": "_:array",
+			"o": "_:object",
+			"z": "_:null"
+		}`)).
+		Should(it.Json(doc).Equiv(`{"s": "_:string|null", "z": "_:string|null"}`)).
+		Should(it.Json(doc).Equiv(`{"a": ["_:number"]}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"s": "_:number"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"n": "_:string"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"b": "_:null"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"a": "_:object"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"o": "_:array"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"z": "_:bool"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"x": "_:null"}`))
+}
+
+func TestJsonOptionalKey(t *testing.T) {
+	doc := `{"name": "bob", "age": 30}`
+
+	it.Then(t).
+		Should(it.Json(doc).Equiv(`{"name": "bob", "email?": "_:string"}`)).
+		Should(it.Json(doc).Equiv(`{"age?": "_:number"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"age?": "_:string"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"email": "_:string"}`)).
+		Should(it.Json(`{"q?": 1}`).Equiv(`{"q?": 1}`))
+}
+
+func TestJsonNumber(t *testing.T) {
+	doc := `{"price": 9.99, "score": 7, "latency": 0.25, "pi": 3.1415}`
+
+	it.Then(t).
+		Should(it.Json(doc).Equiv(`{"price": "num:>0", "score": "num:>=7", "latency": "num:<1"}`)).
+		Should(it.Json(doc).Equiv(`{"score": "num:<=7", "price": "num:!=0"}`, `{"score": "num:=7"}`)).
+		Should(it.Json(doc).Equiv(`{"score": "num:[1,10)", "latency": "num:(0,1]", "price": "num:[1,)"}`)).
+		Should(it.Json(doc).Equiv(`{"pi": "num:~3.14±0.01"}`, `{"pi": "num:~3.14+-0.01"}`)).
+		Should(it.Json(doc).Equiv(`{"score": "num:int"}`, `{"score": "num:int&>0"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"price": "num:<0"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"score": "num:[1,7)"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"latency": "num:(0.25,1]"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"price": "num:(,9.99)"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"pi": "num:~3.14±0.001"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"price": "num:int"}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"price": "num:int&>0"}`)).
+		ShouldNot(it.Json(`{"price": "9.99"}`).Equiv(`{"price": "num:>0"}`)).
+		Should(it.String(it.Json(doc).Equiv(`{"price": "num:>x"}`).Error()).Contain("invalid number expression >x")).
+		Should(it.String(it.Json(doc).Equiv(`{"price": "num:?"}`).Error()).Contain("invalid number expression ?"))
+}
+
+func TestJsonStrict(t *testing.T) {
+	doc := `{"name": "bob", "tags": ["a", "b"], "meta": {"id": 1, "rev": 2}}`
+
+	it.Then(t).
+		Should(it.Json(doc).Equal(`{"name": "_", "tags": ["a", "b"], "meta": {"id": 1, "rev": 2}}`)).
+		Should(it.Json(doc).Equal(`{"name": "_", "tags": "_", "meta": {"id": 1, "...": "_"}}`)).
+		Should(it.Json(doc).Equal(`{"name": "_", "email?": "_", "tags": ["a", "..."], "meta": "_"}`)).
+		Should(it.Json(doc).Equal(`{"name?": "_", "...": "_"}`)).
+		ShouldNot(it.Json(doc).Equal(`{"name": "_", "tags": "_"}`)).
+		ShouldNot(it.Json(doc).Equal(`{"name": "_", "tags": "_", "meta": {"id": 1}}`)).
+		ShouldNot(it.Json(doc).Equal(`{"name": "_", "tags": ["a"], "meta": "_"}`)).
+		ShouldNot(it.Json(doc).Equal(`{"name": "_", "tags": ["a", "b", "c"], "meta": "_"}`)).
+		Should(it.Json(doc).Equiv(`{"name": "_"}`)).
+		Should(it.Json(doc).Equiv(`{"meta": {"...": "_"}}`)).
+		ShouldNot(it.Json(`[1]`).Equiv(`[1, 2, 3]`)).
+		ShouldNot(it.Json(`[1]`).Equiv(`[1, 2, "..."]`)).
+		Should(it.Json(`[1, 2]`).Equiv(`[1, "..."]`))
+}
+
+func TestJsonUnordered(t *testing.T) {
+	doc := `{"ids": [3, 1, 2], "users": [{"id": 2, "name": "b"}, {"id": 1, "name": "a"}]}`
+
+	it.Then(t).
+		Should(it.Json(doc).Equiv(`{"ids": ["@unordered", 1, 2, 3]}`)).
+		Should(it.Json(doc).Equiv(`{"ids": ["@unordered", "num:>2", "num:<3", "_"]}`)).
+		Should(it.Json(doc).Equiv(`{"users": ["@unordered", {"id": 1}, {"id": 2, "name": "b"}]}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"ids": ["@unordered", 1, 2]}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"ids": ["@unordered", 1, 2, 3, 4]}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"ids": ["@unordered", 1, 1, 2]}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"users": ["@unordered", {"id": 1, "name": "b"}, "_"]}`))
+}
+
+func TestJsonContains(t *testing.T) {
+	doc := `{"ids": [3, 1, 2, 1]}`
+
+	it.Then(t).
+		Should(it.Json(doc).Equiv(`{"ids": ["@contains", 2, 1]}`)).
+		Should(it.Json(doc).Equiv(`{"ids": ["@contains", 1, 1]}`)).
+		Should(it.Json(doc).Equiv(`{"ids": ["@contains"]}`)).
+		Should(it.Json(doc).Equal(`{"ids": ["@contains", 3]}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"ids": ["@contains", 4]}`)).
+		ShouldNot(it.Json(doc).Equiv(`{"ids": ["@contains", 3, 3]}`))
+}
+
+func TestJsonCapture(t *testing.T) {
+	resp := `{"id": "8d1f", "owner": {"id": "u1"}, "links": [{"self": "8d1f"}], "n": 10}`
+
+	t.Run("Map", func(t *testing.T) {
+		vars := map[string]any{}
+
+		it.Then(t).
+			Should(it.Json(resp).Bind(vars).Equiv(`{"id": "{{id}}", "owner": {"id": "$owner:regex:^u"}, "n": "$n:num:>0"}`)).
+			Should(it.Equiv(vars, map[string]any{"id": "8d1f", "owner": "u1", "n": 10.0}))
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		var vars struct {
+			ID    string  `json:"id"`
+			Owner string  `json:"owner"`
+			N     float64 `json:"n"`
+		}
+
+		it.Then(t).
+			Should(it.Json(resp).Bind(&vars).Equiv(`{"id": "{{id}}", "owner": {"id": "{{owner}}"}, "n": "{{n}}"}`)).
+			Should(it.Equal(vars.ID, "8d1f")).
+			Should(it.Equal(vars.Owner, "u1")).
+			Should(it.Equal(vars.N, 10.0))
+	})
+
+	t.Run("Consistency", func(t *testing.T) {
+		it.Then(t).
+			Should(it.Json(resp).Equiv(`{"id": "{{id}}", "links": [{"self": "{{id}}"}]}`)).
+			ShouldNot(it.Json(resp).Equiv(`{"id": "{{id}}", "owner": {"id": "{{id}}"}}`)).
+			Should(it.Json(resp).Equiv(`{"id": "{{id}}"}`, `{"links": [{"self": "{{id}}"}]}`)).
+			ShouldNot(it.Json(resp).Equiv(`{"id": "$id:_:number"}`)).
+			Should(it.Json(`[1, 1]`).Equiv(`["@unordered", "{{x}}", "{{x}}"]`)).
+			ShouldNot(it.Json(`[1, 2]`).Equiv(`["@unordered", "{{x}}", "{{x}}"]`)).
+			ShouldNot(it.Json(`[1, 2, 3]`).Equiv(`["@contains", "{{x}}", "{{x}}"]`)).
+			ShouldNot(it.Json(`{"id": 2, "seq": [1, 3]}`).Equiv(`{"id": "{{x}}", "seq": ["@contains", "{{x}}"]}`))
+	})
+
+	t.Run("Reuse", func(t *testing.T) {
+		vars := map[string]any{}
+
+		it.Then(t).
+			Should(it.Json(resp).Bind(vars).Equiv(`{"id": "{{id}}"}`)).
+			Should(it.Json(`{"ref": "8d1f"}`).Bind(vars).Equiv(`{"ref": "{{id}}"}`)).
+			ShouldNot(it.Json(`{"ref": "xxxx"}`).Bind(vars).Equiv(`{"ref": "{{id}}"}`))
+	})
+
+	t.Run("Unordered", func(t *testing.T) {
+		vars := map[string]any{}
+
+		it.Then(t).
+			Should(it.Json(`[{"k": "a", "v": 1}, {"k": "b", "v": 2}]`).Bind(vars).Equiv(`["@unordered", {"k": "b", "v": "{{b}}"}, {"k": "a", "v": "{{a}}"}]`)).
+			Should(it.Equiv(vars, map[string]any{"a": 1.0, "b": 2.0}))
+	})
+
+	t.Run("InvalidTarget", func(t *testing.T) {
+		var vars struct{ ID string }
+
+		it.Then(t).
+			ShouldNot(it.Json(resp).Bind(vars).Equiv(`{"id": "{{id}}"}`))
+	})
+}
+
+func TestJsonDiff(t *testing.T) {
+	doc := `{
+		"id": "o1",
+		"owner": {"name": "bob", "age": 17, "tags": ["a", "b", "c"]},
+		"items": [{"sku": "A1", "qty": 1}, {"sku": "B2", "qty": 2}, {"sku": "C3", "qty": 3}],
+		"extra": true
+	}`
+
+	pattern := `{
+		"id": "o2",
+		"owner": {"name": "bob", "age": "num:>=18", "email": "_", "tags": ["a", "x"]},
+		"items": [{"sku": "A1", "qty": 1}, {"sku": "B2", "qty": 5}],
+		"status": "new"
+	}`
+
+	expect := strings.Join([]string{
+		"be matching",
+		"  {",
+		`+   "extra": true (unexpected)`,
+		`~   "id": "o2" => "o1" (changed)`,
+		`    "items": [`,
+		`      [1]: {`,
+		`~       "qty": 5 => 2 (changed)`,
+		`      }`,
+		`+     [2]: {"qty":3,"sku":"C3"} (unexpected)`,
+		`    ]`,
+		`    "owner": {`,
+		`~     "age": "num:>=18" => 17 (changed)`,
+		`-     "email": "_" (missing)`,
+		`      "tags": [`,
+		`~       [1]: "x" => "b" (changed)`,
+		`+       [2]: "c" (unexpected)`,
+		`      ]`,
+		`    }`,
+		`-   "status": "new" (missing)`,
+		"  }",
+	}, "\n")
+
+	for i := 0; i < 10; i++ {
+		err := it.Json(doc).Equal(pattern)
+		it.Then(t).
+			ShouldNot(err).
+			Should(it.Text(err.Error()).Equal(expect))
+	}
+
+	err := it.Json(`[1, 2, 3]`).Equiv(`["@unordered", 3, 4, 1]`)
+	it.Then(t).Should(it.Text(err.Error()).Equal(strings.Join([]string{
+		"be matching",
+		"  [",
+		`-   4 (missing)`,
+		`+   [1]: 2 (unexpected)`,
+		"  ]",
+	}, "\n")))
+
+	err = it.Json(`"x"`).Equiv(`{"a": 1}`)
+	it.Then(t).Should(it.Text(err.Error()).Equal(`be matching` + "\n" + `~ {"a":1} => "x" (changed)`))
+}
+
+func TestJsonInvalidPattern(t *testing.T) {
+	doc := `{"name": "bob", "tags": ["a", "b"], "age": 18, "a/b": "x"}`
+
+	for _, spec := range []struct{ pattern, path string }{
+		{`"regex:("`, "(root)"},
+		{`{"name": "regex:[a-"}`, "/name"},
+		{`{"tags": ["_", "m/(?P<x/"]}`, "/tags/1"},
+		{`{"a/b": "$v:regex:*"}`, "/a~1b"},
+		{`{"age": "num:>x"}`, "/age"},
+		{`{"age": "num:int&~18"}`, "/age"},
+	} {
+		err := it.Json(doc).Equiv(spec.pattern)
+		it.Then(t).
+			Should(it.String(err.Error()).Contain("at path " + spec.path + " be valid"))
+	}
+
+	it.Then(t).
+		Should(it.Json(`{"name": "m/"}`).Equiv(`{"name": "m/"}`)).
+		Should(it.Json(`{"name": "bob"}`).Equiv(`{"name": "regex:^b"}`, `{"name": "regex:^b"}`))
+}
+
+func TestJsonUsing(t *testing.T) {
+	doc := it.Json(`{"name": "bob", "age": 17, "tags": ["a", "bb"]}`).
+		Using("adult", it.GreaterOrEqualTo(18.0)).
+		Using("minor", it.LessThan(18)).
+		Using("short", func(x string) error { return it.String(x).HaveLen(1) })
+
+	it.Then(t).
+		Should(doc.Equiv(`{"age": "match:minor"}`)).
+		Should(doc.Equiv(`{"tags": ["match:short", "_"]}`)).
+		Should(doc.Equiv(`{"age": "$age:match:minor"}`)).
+		ShouldNot(doc.Equiv(`{"age": "match:adult"}`)).
+		ShouldNot(doc.Equiv(`{"name": "match:minor"}`)).
+		ShouldNot(doc.Equiv(`{"tags": ["_", "match:short"]}`))
+
+	msg := doc.Equiv(`{"age": "match:adult"}`).Error()
+	it.Then(t).
+		Should(it.String(msg).Contain("match:adult, 17 be greater or equal to 18"))
+
+	for pattern, reason := range map[string]string{
+		`{"age": "match:senior"}`: "at path /age be valid: matcher senior be registered with Using",
+	} {
+		it.Then(t).
+			Should(it.String(doc.Equiv(pattern).Error()).Contain(reason))
+	}
+
+	err := it.Json(`{}`).Using("even", func(x int) bool { return x%2 == 0 }).Equiv(`{}`)
+	it.Then(t).
+		Should(it.String(err.Error()).Contain("matcher even be valid"))
+}
@@ -0,0 +1,340 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//
+// Text
+//
+
+// TextOf is multi-line text matcher
+type TextOf struct {
+	text           string
+	trailingSpace  bool
+	lineEndings    bool
+	indent         bool
+	contextOfLines int
+}
+
+// Text creates multi-line text matcher
+//
+//	it.Should(it.Text(x).Equal(y))
+func Text(x string) TextOf {
+	return TextOf{text: x, contextOfLines: 3}
+}
+
+// IgnoreTrailingSpace excludes trailing spaces and tabs from comparison
+func (x TextOf) IgnoreTrailingSpace() TextOf {
+	x.trailingSpace = true
+	return x
+}
+
+// IgnoreLineEndings treats \r\n, \r and \n as equal line endings
+func (x TextOf) IgnoreLineEndings() TextOf {
+	x.lineEndings = true
+	return x
+}
+
+// IgnoreIndent excludes leading spaces and tabs from comparison
+func (x TextOf) IgnoreIndent() TextOf {
+	x.indent = true
+	return x
+}
+
+// WithContext defines number of unchanged lines printed around changes,
+// negative number is treated as zero
+func (x TextOf) WithContext(n int) TextOf {
+	x.contextOfLines = max(n, 0)
+	return x
+}
+
+// Equal checks equality of texts, the unified diff is reported on failure.
+// The diff denotes expected lines with "-" and actual lines with "+".
+//
+//	it.Should(it.Text(x).Equal(y))
+func (x TextOf) Equal(y string) error {
+	assert := "text be equal to expected"
+
+	actual := x.lines(x.text)
+	expect := x.lines(y)
+	edits := diffLines(x.normalize(expect), x.normalize(actual))
+
+	for _, e := range edits {
+		if e.op != opEq {
+			var sb strings.Builder
+			writeUnifiedDiff(&sb, expect, actual, edits, x.contextOfLines)
+			return fmt.Errorf("%s\n%s", assert, strings.TrimSuffix(sb.String(), "\n"))
+		}
+	}
+
+	return passed(errors.New(assert))
+}
+
+func (x TextOf) lines(s string) []string {
+	if x.lineEndings {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+		s = strings.ReplaceAll(s, "\r", "\n")
+	}
+	return strings.Split(s, "\n")
+}
+
+func (x TextOf) normalize(lines []string) []string {
+	seq := make([]string, len(lines))
+	for i, l := range lines {
+		if x.trailingSpace {
+			l = strings.TrimRight(l, " \t")
+		}
+		if x.indent {
+			l = strings.TrimLeft(l, " \t")
+		}
+		seq[i] = l
+	}
+	return seq
+}
+
+// Equal checks equality of strings, the line-based diff is reported on failure.
+//
+//	it.Should(it.String(x).Equal(y))
+func (x String) Equal(y string) error {
+	return Text(string(x)).Equal(y)
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	opEq = iota
+	opDel
+	opIns
+)
+
+// edit script element, a is index at old sequence, b is index at new one
+type edit struct {
+	op   int
+	a, b int
+}
+
+// diffLines computes the shortest edit script using linear space variant of
+// Myers' O(ND) algorithm, the problem is split by the middle snake.
+func diffLines(a, b []string) []edit {
+	return diffRange(make([]edit, 0, len(a)+len(b)), a, b, 0, len(a), 0, len(b))
+}
+
+// diffRange appends edit script of a[x0:x1] and b[y0:y1] to the sequence
+func diffRange(seq []edit, a, b []string, x0, x1, y0, y1 int) []edit {
+	for x0 < x1 && y0 < y1 && a[x0] == b[y0] {
+		seq = append(seq, edit{opEq, x0, y0})
+		x0++
+		y0++
+	}
+
+	suffix := 0
+	for x0 < x1 && y0 < y1 && a[x1-1] == b[y1-1] {
+		x1--
+		y1--
+		suffix++
+	}
+
+	switch {
+	case x0 == x1:
+		for y := y0; y < y1; y++ {
+			seq = append(seq, edit{opIns, x0, y})
+		}
+	case y0 == y1:
+		for x := x0; x < x1; x++ {
+			seq = append(seq, edit{opDel, x, y0})
+		}
+	default:
+		sx, sy, ex, ey := middleSnake(a[x0:x1], b[y0:y1])
+		seq = diffRange(seq, a, b, x0, x0+sx, y0, y0+sy)
+		for i := 0; i < ex-sx; i++ {
+			seq = append(seq, edit{opEq, x0 + sx + i, y0 + sy + i})
+		}
+		seq = diffRange(seq, a, b, x0+ex, x1, y0+ey, y1)
+	}
+
+	for i := 0; i < suffix; i++ {
+		seq = append(seq, edit{opEq, x1 + i, y1 + i})
+	}
+
+	return seq
+}
+
+// middleSnake finds the snake (x0, y0) → (x1, y1) in the middle of the
+// shortest edit script, searching forward from the start and backward from
+// the end simultaneously. The a and b differ at first and last lines.
+func middleSnake(a, b []string) (int, int, int, int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	dmax := (n + m + 1) / 2
+	off := dmax + 1
+
+	// vf is furthest x at diagonal k = x - y of forward paths,
+	// vb is furthest distance from the end at diagonal delta - k of backward ones
+	vf := make([]int, 2*dmax+3)
+	vb := make([]int, 2*dmax+3)
+
+	for d := 0; d <= dmax; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+
+			if kr := delta - k; odd && kr >= -(d-1) && kr <= d-1 && x+vb[off+kr] >= n {
+				return sx, sy, x, y
+			}
+		}
+
+		for kr := -d; kr <= d; kr += 2 {
+			var u int
+			if kr == -d || (kr != d && vb[off+kr-1] < vb[off+kr+1]) {
+				u = vb[off+kr+1]
+			} else {
+				u = vb[off+kr-1] + 1
+			}
+			w := u - kr
+			su, sw := u, w
+			for u < n && w < m && a[n-1-u] == b[m-1-w] {
+				u++
+				w++
+			}
+			vb[off+kr] = u
+
+			if k := delta - kr; !odd && k >= -d && k <= d && vf[off+k]+u >= n {
+				return n - u, m - w, n - su, m - sw
+			}
+		}
+	}
+
+	// unreachable, the paths overlap at most after dmax steps
+	return 0, 0, n, m
+}
+
+// writeUnifiedDiff renders edit script as hunks surrounded by context lines
+func writeUnifiedDiff(sb *strings.Builder, a, b []string, edits []edit, context int) {
+	for i := 0; i < len(edits); {
+		if edits[i].op == opEq {
+			i++
+			continue
+		}
+
+		// expand hunk while changes are separated by less than 2*context lines
+		lo := max(i-context, 0)
+		hi := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].op != opEq {
+				hi = j
+				continue
+			}
+			if j-hi > 2*context {
+				break
+			}
+		}
+		hi = min(hi+context, len(edits)-1)
+
+		writeHunk(sb, a, b, edits[lo:hi+1])
+		i = hi + 1
+	}
+}
+
+func writeHunk(sb *strings.Builder, a, b []string, edits []edit) {
+	as, bs := edits[0].a, edits[0].b
+	an, bn := 0, 0
+	for _, e := range edits {
+		switch e.op {
+		case opEq:
+			an++
+			bn++
+		case opDel:
+			an++
+		case opIns:
+			bn++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("\x1b[36m@@ -%d,%d +%d,%d @@\x1b[0m\n", as+1, an, bs+1, bn))
+
+	for i := 0; i < len(edits); {
+		switch edits[i].op {
+		case opEq:
+			sb.WriteString(fmt.Sprintf("  %s\n", a[edits[i].a]))
+			i++
+		default:
+			// block of deletions followed by insertions
+			j := i
+			for j < len(edits) && edits[j].op == opDel {
+				j++
+			}
+			k := j
+			for k < len(edits) && edits[k].op == opIns {
+				k++
+			}
+			dels, inss := edits[i:j], edits[j:k]
+
+			for p, e := range dels {
+				if len(dels) == len(inss) {
+					writeChangedLine(sb, "-", "\x1b[33m", "\x1b[1;43m", a[e.a], b[inss[p].b])
+				} else {
+					sb.WriteString(fmt.Sprintf("\x1b[33m- %s\x1b[0m\n", a[e.a]))
+				}
+			}
+			for p, e := range inss {
+				if len(dels) == len(inss) {
+					writeChangedLine(sb, "+", "\x1b[31m", "\x1b[1;41m", b[e.b], a[dels[p].a])
+				} else {
+					sb.WriteString(fmt.Sprintf("\x1b[31m+ %s\x1b[0m\n", b[e.b]))
+				}
+			}
+			i = k
+		}
+	}
+}
+
+// writeChangedLine highlights the segment of line that differs from its pair
+func writeChangedLine(sb *strings.Builder, sign, color, highlight, line, pair string) {
+	x, y := []rune(line), []rune(pair)
+
+	p := 0
+	for p < len(x) && p < len(y) && x[p] == y[p] {
+		p++
+	}
+
+	s := 0
+	for s < len(x)-p && s < len(y)-p && x[len(x)-1-s] == y[len(y)-1-s] {
+		s++
+	}
+
+	sb.WriteString(color)
+	sb.WriteString(sign)
+	sb.WriteString(" ")
+	sb.WriteString(string(x[:p]))
+	if mid := x[p : len(x)-s]; len(mid) != 0 {
+		sb.WriteString("\x1b[0m")
+		sb.WriteString(highlight)
+		sb.WriteString(string(mid))
+		sb.WriteString("\x1b[0m")
+		sb.WriteString(color)
+	}
+	sb.WriteString(string(x[len(x)-s:]))
+	sb.WriteString("\x1b[0m\n")
+}
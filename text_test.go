@@ -0,0 +1,88 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fogfish/it/v2"
+)
+
+func TestTextEqual(t *testing.T) {
+	text := "a\nb\nc\nd"
+
+	it.Then(t).
+		Should(it.Text(text).Equal(text)).
+		Should(it.Text("").Equal("")).
+		ShouldNot(it.Text(text).Equal("a\nb\nx\nd")).
+		ShouldNot(it.Text(text).Equal("a\nb\nc")).
+		ShouldNot(it.Text("").Equal("a")).
+		Should(it.String(text).Equal(text)).
+		ShouldNot(it.String(text).Equal("a\nc\nd"))
+}
+
+func TestTextEqualOptions(t *testing.T) {
+	it.Then(t).
+		ShouldNot(it.Text("a  \nb\t").Equal("a\nb")).
+		Should(it.Text("a  \nb\t").IgnoreTrailingSpace().Equal("a\nb")).
+		ShouldNot(it.Text("a\r\nb\r\n").Equal("a\nb\n")).
+		Should(it.Text("a\r\nb\r\n").IgnoreLineEndings().Equal("a\nb\n")).
+		ShouldNot(it.Text("a\n  b\n\tc").Equal("a\nb\nc")).
+		Should(it.Text("a\n  b\n\tc").IgnoreIndent().Equal("a\nb\nc"))
+}
+
+func TestTextDiff(t *testing.T) {
+	seq := make([]string, 20)
+	for i := range seq {
+		seq[i] = string(rune('a' + i))
+	}
+	expect := strings.Join(seq, "\n")
+
+	seq[2] = "C"
+	seq[17] = "R"
+	actual := strings.Join(seq, "\n")
+
+	msg := it.Text(actual).Equal(expect).Error()
+
+	it.Then(t).
+		Should(it.String(msg).Contain("@@ -1,6 +1,6 @@")).
+		Should(it.String(msg).Contain("@@ -15,6 +15,6 @@")).
+		ShouldNot(it.String(msg).Contain("  j\n")).
+		Should(it.String(msg).Count("@@ -", 2))
+
+	msg = it.Text(actual).WithContext(10).Equal(expect).Error()
+
+	it.Then(t).
+		Should(it.String(msg).Contain("@@ -1,20 +1,20 @@")).
+		Should(it.String(msg).Count("@@ -", 1)).
+		ShouldNot(it.String(msg).HaveSuffix("\n"))
+
+	msg = it.Text(actual).WithContext(-1).Equal(expect).Error()
+
+	it.Then(t).
+		Should(it.String(msg).Contain("@@ -3,1 +3,1 @@")).
+		Should(it.String(msg).Count("@@ -", 2))
+}
+
+func TestTextDiffLarge(t *testing.T) {
+	a := make([]string, 5000)
+	b := make([]string, 5000)
+	for i := range a {
+		a[i] = "a" + strings.Repeat("x", i%7)
+		b[i] = "b" + strings.Repeat("x", i%7)
+	}
+	b[2500] = a[2500]
+
+	msg := it.Text(strings.Join(b, "\n")).WithContext(0).Equal(strings.Join(a, "\n")).Error()
+
+	it.Then(t).
+		Should(it.String(msg).Count("\n\x1b[33m- ", 4999)).
+		Should(it.String(msg).Count("\n\x1b[31m+ ", 4999))
+}
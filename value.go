@@ -0,0 +1,465 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+//
+// Nested values
+//
+
+// ValueOf is reference to nested value of structs, maps, slices and pointers
+type ValueOf struct {
+	val  reflect.Value
+	path string
+	err  error
+}
+
+// Value creates reference to the value, use At to navigate nested elements
+//
+//	it.Should(it.Value(obj).At("Orders[2].Lines[0].SKU").Equal("A1"))
+func Value(x any) ValueOf {
+	return ValueOf{val: addressable(reflect.ValueOf(x))}
+}
+
+// At resolves the path relative to the value. The path is a sequence of
+// struct fields or map keys separated by dot and indexes of slices, arrays or
+// maps in square brackets, e.g. Orders[2].Lines[0].SKU or Attrs["color"].
+// The malformed path and unknown struct field are typos of the expectation,
+// the assert fails regardless of the keyword polarity.
+func (x ValueOf) At(path string) ValueOf {
+	if x.err != nil {
+		return x
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return ValueOf{path: x.path + path, err: invalidInput(err)}
+	}
+
+	v := x.val
+	at := x.path
+	for _, s := range segments {
+		if v, err = s.resolve(v); err != nil {
+			return ValueOf{path: at + s.String(at), err: err}
+		}
+		at = at + s.String(at)
+	}
+
+	return ValueOf{val: v, path: at}
+}
+
+// Value returns resolved value or error if the path does not resolve
+func (x ValueOf) Value() (any, error) {
+	if x.err != nil {
+		return nil, x.resolvable()
+	}
+
+	if !x.val.IsValid() {
+		return nil, nil
+	}
+
+	return x.val.Interface(), nil
+}
+
+// Equal checks equality (x ≈ y) of the resolved value, the expected number is
+// converted to the type of value if conversion is lossless.
+//
+//	it.Should(it.Value(obj).At("Name").Equal("bob"))
+func (x ValueOf) Equal(y any) error {
+	v, err := x.Value()
+	if err != nil {
+		return err
+	}
+
+	y = convertNumber(reflect.ValueOf(v), y)
+
+	assert := fmt.Errorf("value %v at %s be equal to %v", v, x.pathOf(), y)
+	if v != nil && y != nil && reflect.TypeOf(v) != reflect.TypeOf(y) {
+		assert = fmt.Errorf("value %v (%T) at %s be equal to %v (%T)", v, v, x.pathOf(), y, y)
+	}
+
+	if !equal(v, y) {
+		return assert
+	}
+
+	return passed(assert)
+}
+
+func (x ValueOf) pathOf() string {
+	if x.path == "" {
+		return "."
+	}
+	return x.path
+}
+
+func (x ValueOf) resolvable() error {
+	return fmt.Errorf("path %s be resolvable: %w", x.pathOf(), x.err)
+}
+
+// ValueAs applies the assert to resolved value of type T, it hands the value
+// to String, Seq, Map and other typed matchers
+//
+//	it.Should(it.ValueAs(it.Value(obj).At("Orders"), func(xs []Order) error {
+//	  return it.Seq(xs).HaveLen(3)
+//	}))
+func ValueAs[T any](x ValueOf, f func(T) error) error {
+	v, err := x.Value()
+	if err != nil {
+		return err
+	}
+
+	t, ok := v.(T)
+	if !ok {
+		return fmt.Errorf("value %v at %s be of type %T", v, x.pathOf(), *new(T))
+	}
+
+	return nested(fmt.Errorf("value at %s", x.pathOf()), f(t))
+}
+
+//
+// Struct
+//
+
+// StructOf is reference to struct value
+type StructOf struct{ ValueOf }
+
+// Struct creates reference to the struct
+//
+//	it.Should(it.Struct(obj).Field("Name").Equal("bob"))
+func Struct(x any) StructOf {
+	return StructOf{Value(x)}
+}
+
+// Field resolves the field of the struct
+func (x StructOf) Field(name string) ValueOf {
+	return x.At(name)
+}
+
+// Fields is partial expectation of struct fields. The key is name of field or
+// path to nested one (see ValueOf.At). The value is either expected value,
+// assert function func(T) error, Matcher[T], nested Fields or Anything.
+//
+//	it.Fields{
+//	  "Name":       "bob",
+//	  "Email":      it.EqualTo("bob@example.com"),
+//	  "Age":        func(x int) error { return it.Greater(x, 18) },
+//	  "Address":    it.Fields{"City": "Helsinki"},
+//	  "Orders[0]":  it.Anything,
+//	}
+type Fields map[string]any
+
+type anything struct{}
+
+// Anything matches any value, it asserts existence of the field only
+var Anything = anything{}
+
+// Match checks fields of struct against partial expectation, unlisted fields
+// are ignored. The values are compared directly without JSON round-trip,
+// therefore unexported fields, time and int64 precision are preserved.
+//
+//	it.Should(it.Struct(x).Match(it.Fields{"Name": "bob"}))
+func (x StructOf) Match(fields Fields) error {
+	v, err := x.Value()
+	if err != nil {
+		return err
+	}
+
+	assert := fmt.Errorf("struct %+v match fields", v)
+
+	failed, invalid := matchFields(x.ValueOf, fields)
+	if len(failed) != 0 {
+		assert = fmt.Errorf("%w\n\t%s", assert, strings.Join(failed, "\n\t"))
+	}
+
+	switch {
+	case invalid:
+		return invalidInput(assert)
+	case len(failed) != 0:
+		return assert
+	default:
+		return passed(assert)
+	}
+}
+
+// matchFields returns failed fields, the flag reports the invalid expectation
+func matchFields(x ValueOf, fields Fields) ([]string, bool) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	failed := make([]string, 0)
+	invalid := false
+	for _, k := range keys {
+		v := x.At(k)
+		if v.err != nil {
+			failed = append(failed, v.resolvable().Error())
+			invalid = invalid || isInvalid(v.err)
+			continue
+		}
+
+		switch expect := fields[k].(type) {
+		case anything:
+		case Fields:
+			seq, inv := matchFields(v, expect)
+			failed = append(failed, seq...)
+			invalid = invalid || inv
+		default:
+			if err := v.match(expect); !IsPassed(err) {
+				failed = append(failed, err.Error())
+				invalid = invalid || isInvalid(err)
+			}
+		}
+	}
+
+	return failed, invalid
+}
+
+var typeOfError = reflect.TypeFor[error]()
+
+// assertOf returns assert function func(T) error of the expectation, it is
+// either the function itself or Match method of Matcher[T]
+func assertOf(expect any) (reflect.Value, bool) {
+	f := reflect.ValueOf(expect)
+	if f.IsValid() && f.Kind() != reflect.Func {
+		f = f.MethodByName("Match")
+	}
+
+	if !f.IsValid() || f.Kind() != reflect.Func || f.Type().NumIn() != 1 || f.Type().NumOut() != 1 || f.Type().Out(0) != typeOfError {
+		return reflect.Value{}, false
+	}
+
+	return f, true
+}
+
+// match resolved value against expected value, assert function or Matcher[T]
+func (x ValueOf) match(expect any) error {
+	if f, ok := assertOf(expect); ok {
+		// values of map[string]any and other interfaces are matched by
+		// their dynamic type
+		v := x.val
+		for v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if !v.IsValid() || !v.Type().AssignableTo(f.Type().In(0)) {
+			return fmt.Errorf("value at %s be of type %s", x.pathOf(), f.Type().In(0))
+		}
+
+		err, _ := f.Call([]reflect.Value{v})[0].Interface().(error)
+		return nested(fmt.Errorf("value at %s", x.pathOf()), err)
+	}
+
+	return x.Equal(expect)
+}
+
+// convertNumber converts expected number to the type of value if conversion
+// is lossless, e.g. untyped constant 18 is compared with int64 field.
+func convertNumber(v reflect.Value, expect any) any {
+	f := reflect.ValueOf(expect)
+	if !v.IsValid() || !f.IsValid() || f.Type() == v.Type() || !isNumber(f.Kind()) || !isNumber(v.Kind()) {
+		return expect
+	}
+
+	// the round-trip does not detect wrap-around of signed and unsigned
+	// integers, e.g. -1 and math.MaxUint64
+	switch {
+	case isSigned(f.Kind()) && isUnsigned(v.Kind()) && f.Int() < 0:
+		return expect
+	case isUnsigned(f.Kind()) && isSigned(v.Kind()) && (f.Uint() > math.MaxInt64 || v.OverflowInt(int64(f.Uint()))):
+		return expect
+	}
+
+	if f.CanConvert(v.Type()) && f.Convert(v.Type()).Convert(f.Type()).Equal(f) {
+		return f.Convert(v.Type()).Interface()
+	}
+
+	return expect
+}
+
+func isSigned(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUnsigned(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// segment of the path, either field (key) or index
+type segment struct {
+	name    string
+	index   string
+	isIndex bool
+}
+
+func (s segment) String(prefix string) string {
+	if s.isIndex {
+		return "[" + s.index + "]"
+	}
+	if prefix == "" {
+		return s.name
+	}
+	return "." + s.name
+}
+
+func parsePath(path string) ([]segment, error) {
+	seq := make([]segment, 0)
+
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			j := strings.IndexByte(path[i:], ']')
+			if j == -1 {
+				return nil, fmt.Errorf("unclosed bracket at %d", i)
+			}
+			index := path[i+1 : i+j]
+			if uq, err := strconv.Unquote(index); err == nil {
+				index = uq
+			}
+			seq = append(seq, segment{index: index, isIndex: true})
+			i = i + j + 1
+		default:
+			j := strings.IndexAny(path[i:], ".[")
+			if j == -1 {
+				j = len(path) - i
+			}
+			seq = append(seq, segment{name: path[i : i+j]})
+			i = i + j
+		}
+	}
+
+	return seq, nil
+}
+
+func (s segment) resolve(v reflect.Value) (reflect.Value, error) {
+	if !v.IsValid() {
+		return v, fmt.Errorf("%s of nil is not navigable", s.String(""))
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, fmt.Errorf("nil %s", v.Type())
+		}
+		v = addressable(v.Elem())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if s.isIndex {
+			return v, fmt.Errorf("index [%s] of struct %s", s.index, v.Type())
+		}
+		f, ok := v.Type().FieldByName(s.name)
+		if !ok {
+			return v, invalidInput(fmt.Errorf("field %s not found at %s", s.name, v.Type()))
+		}
+		return field(v, f)
+	case reflect.Map:
+		key := s.name
+		if s.isIndex {
+			key = s.index
+		}
+		k, err := mapKey(v.Type().Key(), key)
+		if err != nil {
+			return v, err
+		}
+		e := v.MapIndex(k)
+		if !e.IsValid() {
+			return v, fmt.Errorf("key %s not found at %s", key, v.Type())
+		}
+		return addressable(e), nil
+	case reflect.Slice, reflect.Array, reflect.String:
+		if !s.isIndex {
+			return v, fmt.Errorf("field %s of %s is not navigable", s.name, v.Type())
+		}
+		i, err := strconv.Atoi(s.index)
+		if err != nil {
+			return v, fmt.Errorf("index [%s] of %s be integer", s.index, v.Type())
+		}
+		if i < 0 || i >= v.Len() {
+			return v, fmt.Errorf("index %d out of range, length %d", i, v.Len())
+		}
+		return addressable(v.Index(i)), nil
+	default:
+		return v, fmt.Errorf("%s of %s is not navigable", s.String(""), v.Type())
+	}
+}
+
+func mapKey(t reflect.Type, key string) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(key).Convert(t), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("key %s be %s", key, t)
+		}
+		return reflect.ValueOf(i).Convert(t), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(key, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("key %s be %s", key, t)
+		}
+		return reflect.ValueOf(i).Convert(t), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("key of type %s is not supported", t)
+	}
+}
+
+// addressable copies value, so that its fields are accessible
+func addressable(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.CanAddr() {
+		return v
+	}
+
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+// field returns struct field, including unexported ones, as readable value
+func field(v reflect.Value, f reflect.StructField) (reflect.Value, error) {
+	fv, err := v.FieldByIndexErr(f.Index)
+	if err != nil {
+		return v, fmt.Errorf("field %s of %s: %w", f.Name, v.Type(), err)
+	}
+
+	if fv.CanInterface() {
+		return fv, nil
+	}
+
+	// Note: reflect prohibits interface of unexported fields, the value is
+	//       re-created from its address. It is safe to read because the value
+	//       is either addressable by origin or copied by the library.
+	return reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem(), nil
+}
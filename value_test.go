@@ -0,0 +1,235 @@
+//
+// Copyright (C) 2019 Dmitry Kolesnikov
+//
+// This file may be modified and distributed under the terms
+// of the MIT license.  See the LICENSE file for details.
+// https://github.com/fogfish/it
+//
+
+package it_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fogfish/it/v2"
+)
+
+type line struct {
+	SKU string
+	qty int
+}
+
+type order struct {
+	ID    string
+	Lines []line
+	Attrs map[string]any
+	Index map[int]*line
+}
+
+type customer struct {
+	Name   string
+	Orders []*order
+	Meta   any
+}
+
+func fixture() customer {
+	return customer{
+		Name: "bob",
+		Orders: []*order{
+			{ID: "o1"},
+			{
+				ID:    "o2",
+				Lines: []line{{"A1", 1}, {"B2", 5}},
+				Attrs: map[string]any{"color": "red", "size": []int{1, 2}},
+				Index: map[int]*line{7: {"C3", 3}},
+			},
+		},
+		Meta: map[string]string{"k": "v"},
+	}
+}
+
+func TestValueAt(t *testing.T) {
+	obj := fixture()
+
+	it.Then(t).
+		Should(it.Value(obj).At("Name").Equal("bob")).
+		Should(it.Value(&obj).At("Orders[1].Lines[0].SKU").Equal("A1")).
+		Should(it.Value(obj).At("Orders[1]").At("Lines[1].SKU").Equal("B2")).
+		Should(it.Value(obj).At("Orders[1].Lines[1].qty").Equal(5)).
+		Should(it.Value(obj).At("Orders[1].Attrs.color").Equal("red")).
+		Should(it.Value(obj).At(`Orders[1].Attrs["color"]`).Equal("red")).
+		Should(it.Value(obj).At("Orders[1].Attrs.size[1]").Equal(2)).
+		Should(it.Value(obj).At("Orders[1].Index[7].SKU").Equal("C3")).
+		Should(it.Value(obj).At("Orders[1].Index[7].qty").Equal(3)).
+		Should(it.Value(obj).At("Meta.k").Equal("v")).
+		Should(it.Value(obj.Orders).At("[0].ID").Equal("o1")).
+		Should(it.Value(obj).At("Orders[0].Lines").Equal([]line(nil))).
+		ShouldNot(it.Value(obj).At("Name").Equal("alice"))
+}
+
+func TestValueAtUnresolved(t *testing.T) {
+	obj := fixture()
+
+	for path, reason := range map[string]string{
+		"Orders[5]":             "index 5 out of range",
+		"Orders[x]":             "be integer",
+		"Orders[1].Attrs.shape": "key shape not found",
+		"Orders[1].Index[x]":    "key x be int",
+		"Name.First":            "is not navigable",
+	} {
+		err := it.Value(obj).At(path).Equal("x")
+
+		it.Then(t).
+			ShouldNot(err).
+			Should(it.String(err.Error()).Contain(reason)).
+			Should(it.String(err.Error()).Contain("be resolvable"))
+	}
+
+	// typos of the expectation are invalid input, see TestInvalidInput
+	for path, reason := range map[string]string{
+		"Email":       "field Email not found",
+		"Orders[1.ID": "unclosed bracket",
+	} {
+		err := it.Value(obj).At(path).Equal("x")
+
+		it.Then(t).
+			Should(it.String(err.Error()).Contain(reason)).
+			Should(it.String(err.Error()).Contain("be resolvable"))
+	}
+
+	type inner struct{ X int }
+	type outer struct {
+		*inner
+		Name string
+	}
+
+	for _, err := range []error{
+		it.Value(outer{}).At("X").Equal(1),
+		it.Value(nil).At("X").Equal(1),
+	} {
+		it.Then(t).
+			ShouldNot(err).
+			Should(it.String(err.Error()).Contain("nil")).
+			Should(it.String(err.Error()).Contain("be resolvable"))
+	}
+}
+
+func TestValueAs(t *testing.T) {
+	obj := fixture()
+
+	it.Then(t).
+		Should(it.ValueAs(it.Value(obj).At("Orders[1].Lines"), func(xs []line) error {
+			return it.Seq(xs).HaveLen(2)
+		})).
+		Should(it.ValueAs(it.Value(obj).At("Name"), func(x string) error {
+			return it.String(x).HavePrefix("b")
+		})).
+		ShouldNot(it.ValueAs(it.Value(obj).At("Name"), func(x string) error {
+			return it.String(x).HavePrefix("a")
+		})).
+		ShouldNot(it.ValueAs(it.Value(obj).At("Name"), func(x int) error {
+			return nil
+		}))
+
+	err := it.ValueAs(it.Value(obj).At("Name"), func(x string) error { return nil })
+	it.Then(t).
+		Should(err).
+		Should(it.Equal(err.Error(), "value at Name"))
+}
+
+func TestStructField(t *testing.T) {
+	obj := fixture()
+
+	it.Then(t).
+		Should(it.Struct(obj).Field("Name").Equal("bob")).
+		Should(it.Struct(&obj).Field("Name").Equal("bob"))
+}
+
+func TestStructMatch(t *testing.T) {
+	type address struct {
+		City string
+		zip  int64
+	}
+	type user struct {
+		Name    string
+		Age     int
+		Created time.Time
+		Address address
+		secret  string
+	}
+
+	now := time.Now()
+	obj := user{
+		Name:    "bob",
+		Age:     30,
+		Created: now,
+		Address: address{City: "Helsinki", zip: 9007199254740993},
+		secret:  "xyz",
+	}
+	adult := func(x int) error { return it.Greater(x, 18) }
+
+	it.Then(t).
+		Should(it.Struct(obj).Match(it.Fields{"Name": "bob"})).
+		Should(it.Struct(&obj).Match(it.Fields{
+			"Name":         "bob",
+			"Age":          adult,
+			"Created":      func(x time.Time) error { return it.True(x.Equal(now)) },
+			"Address":      it.Fields{"City": "Helsinki", "zip": 9007199254740993},
+			"Address.City": it.Anything,
+			"secret":       "xyz",
+		})).
+		ShouldNot(it.Struct(obj).Match(it.Fields{"Name": "alice"})).
+		ShouldNot(it.Struct(obj).Match(it.Fields{"Age": func(x int) error { return it.Greater(x, 40) }})).
+		ShouldNot(it.Struct(obj).Match(it.Fields{"Age": func(x string) error { return nil }})).
+		ShouldNot(it.Struct(obj).Match(it.Fields{"Address": it.Fields{"zip": 9007199254740992}})).
+		Should(it.Struct(obj).Match(it.Fields{"Name": it.EqualTo("bob"), "Age": it.GreaterThan(18)})).
+		ShouldNot(it.Struct(obj).Match(it.Fields{"Name": it.EqualTo("alice")})).
+		ShouldNot(it.Struct(obj).Match(it.Fields{"Age": it.EqualTo("30")}))
+
+	doc := map[string]any{"age": 20, "rev": int64(7)}
+
+	it.Then(t).
+		Should(it.Struct(doc).Match(it.Fields{"age": adult, "rev": 7})).
+		Should(it.Struct(doc).Match(it.Fields{"age": it.GreaterThan(18)})).
+		Should(it.Value(doc).At("rev").Equal(7)).
+		ShouldNot(it.Struct(doc).Match(it.Fields{"age": func(x string) error { return nil }})).
+		ShouldNot(it.Struct(doc).Match(it.Fields{"rev": 7.5}))
+}
+
+func TestStructMatchMessage(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	msg := it.Struct(user{"bob", 10}).Match(it.Fields{
+		"Name":  "alice",
+		"Age":   func(x int) error { return it.Greater(x, 18) },
+		"Email": "bob@example.com",
+	}).Error()
+
+	it.Then(t).
+		Should(it.String(msg).Contain("value at Age: 10 be greater than 18")).
+		Should(it.String(msg).Contain("path Email be resolvable")).
+		Should(it.String(msg).Contain("value bob at Name be equal to alice"))
+
+	type rev struct{ Rev int64 }
+
+	it.Then(t).
+		Should(it.String(it.Value(rev{18}).At("Rev").Equal(18.5).Error()).Contain("value 18 (int64) at Rev be equal to 18.5 (float64)")).
+		Should(it.String(it.Value(rev{18}).At("Rev").Equal(17).Error()).Contain("value 18 at Rev be equal to 17"))
+}
+
+func TestValueEqualNumber(t *testing.T) {
+	it.Then(t).
+		Should(it.Value(int64(7)).Equal(7)).
+		Should(it.Value(uint8(7)).Equal(7)).
+		Should(it.Value(int8(-1)).Equal(-1)).
+		ShouldNot(it.Value(uint64(math.MaxUint64)).Equal(-1)).
+		ShouldNot(it.Value(uint8(255)).Equal(int8(-1))).
+		ShouldNot(it.Value(int64(-1)).Equal(uint64(math.MaxUint64))).
+		ShouldNot(it.Value(int8(-1)).Equal(uint8(255))).
+		ShouldNot(it.Value(int8(1)).Equal(257))
+}